@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"movie-rating-api/internal/config"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"movie-rating-api/internal/service"
+)
+
+// runImport 批量导入子命令，与 POST /movies:batchImport 使用相同的校验和导入逻辑。
+// 用法: api import [-format json|csv|jsonl] [-mode atomic|best-effort] [-boxoffice] <file|->
+// 返回进程退出码：有失败行时返回1
+func runImport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "input format: json, csv or jsonl (detected from file extension by default)")
+	mode := fs.String("mode", models.ImportModeAtomic, "import mode: atomic or best-effort")
+	fetchBoxOffice := fs.Bool("boxoffice", false, "fetch box office data for each imported movie")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: api import [-format json|csv|jsonl] [-mode atomic|best-effort] [-boxoffice] <file|->")
+		return 2
+	}
+	if *mode != models.ImportModeAtomic && *mode != models.ImportModeBestEffort {
+		fmt.Fprintln(os.Stderr, "mode must be 'atomic' or 'best-effort'")
+		return 2
+	}
+
+	path := fs.Arg(0)
+	detected, err := service.DetectImportFormat(*format, "", path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open %s: %v\n", path, err)
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	db := initDatabase()
+	defer db.Close()
+
+	movieService := service.NewMovieService(
+		repository.NewMovieRepository(db),
+		repository.NewAliasRepository(db),
+		repository.NewGenreRepository(db),
+		repository.NewContentRatingRepository(db),
+		service.NewBoxOfficeService(cfg.BoxOfficeURL, cfg.BoxOfficeAPIKey),
+		cfg.RatingRounding,
+	)
+
+	report, err := movieService.ImportMovies(input, detected, models.ImportOptions{
+		Mode:           *mode,
+		FetchBoxOffice: *fetchBoxOffice,
+	})
+	if err != nil {
+		log.Printf("Import failed: %v", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return 1
+	}
+
+	log.Printf("Import finished: %d created, %d skipped, %d failed", report.Created, report.Skipped, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
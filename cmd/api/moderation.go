@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"movie-rating-api/internal/config"
+	"movie-rating-api/internal/service"
+)
+
+// newAutoModerationConfig 根据配置构建评论自动审核规则，屏蔽词文件无法读取时退出
+func newAutoModerationConfig(cfg *config.Config) service.AutoModerationConfig {
+	moderationConfig := service.DefaultAutoModerationConfig()
+	moderationConfig.AutoApprove = cfg.ReviewAutoApprove
+	moderationConfig.ReportThreshold = cfg.ReviewReportThreshold
+
+	for _, word := range strings.Split(cfg.ReviewBlockedWords, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			moderationConfig.BlockedWords = append(moderationConfig.BlockedWords, word)
+		}
+	}
+
+	if cfg.ReviewBlockedWordsFile != "" {
+		words, err := service.LoadWordList(cfg.ReviewBlockedWordsFile)
+		if err != nil {
+			log.Fatalf("Failed to load blocked words from %s: %v", cfg.ReviewBlockedWordsFile, err)
+		}
+		moderationConfig.BlockedWords = append(moderationConfig.BlockedWords, words...)
+	}
+
+	return moderationConfig
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"movie-rating-api/internal/middleware"
+	"movie-rating-api/internal/models"
+)
+
+// routePolicyRules 路由级授权策略：读操作公开，写操作需要认证及对应的权限。
+// 权限由角色授予（rater评分，editor编辑目录，admin删除及管理密钥和角色）。
+// 新增路由必须在此声明，否则启动时校验失败
+var routePolicyRules = []middleware.RouteRule{
+	{Method: "GET", Path: "/healthz", Access: middleware.AccessPublic},
+
+	{Method: "GET", Path: "/movies", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/movies", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "POST", Path: "/movies/batchImport", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "GET", Path: "/movies/:title/rating", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/movies/:title/ratings", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/movies/:title/rating-events", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/movies/:title/similar", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/movies/:title/reviews", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/movies/:title/ratings", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	// 标签以评分者身份添加，只能撤回自己添加的标签
+	{Method: "GET", Path: "/movies/:title/tags", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/movies/:title/tags", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "DELETE", Path: "/movies/:title/tags/:tag", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "GET", Path: "/movies/:title/releases", Access: middleware.AccessPublic},
+	{Method: "PUT", Path: "/movies/:title/releases/:region/:channel", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "DELETE", Path: "/movies/:title/releases/:region/:channel", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+	{Method: "GET", Path: "/movies/:title/content-ratings", Access: middleware.AccessPublic},
+	{Method: "PUT", Path: "/movies/:title/content-ratings/:system", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "DELETE", Path: "/movies/:title/content-ratings/:system", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+	{Method: "GET", Path: "/movies/:title/aliases", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/movies/:title/aliases", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "DELETE", Path: "/movies/:title/aliases/:aliasId", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+	{Method: "GET", Path: "/movies/:title/credits", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/movies/:title/credits", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "DELETE", Path: "/movies/:title/credits/:creditId", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+
+	{Method: "GET", Path: "/charts/:chart", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/calendar", Access: middleware.AccessPublic},
+
+	{Method: "GET", Path: "/tags", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/tags/trending", Access: middleware.AccessPublic},
+
+	{Method: "GET", Path: "/raters/:id/ratings", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/raters/:id/stats", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/raters/:id/recommendations", Access: middleware.AccessPublic},
+	// 只能撤回自己的评分，管理员代操作在处理器中校验
+	{Method: "DELETE", Path: "/raters/:id/ratings/:title", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "GET", Path: "/raters/:id/watchlist", Access: middleware.AccessPublic},
+
+	// 投票、表情回应和举报以评分者身份进行
+	{Method: "GET", Path: "/reviews/:id", Access: middleware.AccessPublic},
+	{Method: "PUT", Path: "/reviews/:id/vote", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "DELETE", Path: "/reviews/:id/vote", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "PUT", Path: "/reviews/:id/reactions/:reaction", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "DELETE", Path: "/reviews/:id/reactions/:reaction", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+	{Method: "POST", Path: "/reviews/:id/reports", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsWrite},
+
+	// 评论审核仅限版主
+	{Method: "GET", Path: "/moderation/reviews", Access: middleware.AccessAuthenticated, Permission: models.PermReviewsModerate},
+	{Method: "POST", Path: "/moderation/reviews/:id/decision", Access: middleware.AccessAuthenticated, Permission: models.PermReviewsModerate},
+	{Method: "GET", Path: "/moderation/reviews/:id/history", Access: middleware.AccessAuthenticated, Permission: models.PermReviewsModerate},
+
+	// 列表的读取按可见性在处理器中检查，修改还要求是列表所有者
+	{Method: "POST", Path: "/lists", Access: middleware.AccessAuthenticated, Permission: models.PermListsWrite},
+	{Method: "GET", Path: "/lists", Access: middleware.AccessPublic},
+	{Method: "GET", Path: "/lists/:id", Access: middleware.AccessPublic},
+	{Method: "PUT", Path: "/lists/:id", Access: middleware.AccessAuthenticated, Permission: models.PermListsWrite},
+	{Method: "DELETE", Path: "/lists/:id", Access: middleware.AccessAuthenticated, Permission: models.PermListsWrite},
+	{Method: "GET", Path: "/lists/:id/items", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/lists/:id/items", Access: middleware.AccessAuthenticated, Permission: models.PermListsWrite},
+	{Method: "DELETE", Path: "/lists/:id/items/:title", Access: middleware.AccessAuthenticated, Permission: models.PermListsWrite},
+
+	{Method: "GET", Path: "/people", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/people", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "GET", Path: "/people/:id", Access: middleware.AccessPublic},
+	{Method: "PUT", Path: "/people/:id", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "DELETE", Path: "/people/:id", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+	{Method: "GET", Path: "/people/:id/filmography", Access: middleware.AccessPublic},
+
+	{Method: "GET", Path: "/genres", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/genres", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "GET", Path: "/content-rating-systems", Access: middleware.AccessPublic},
+
+	{Method: "GET", Path: "/collections", Access: middleware.AccessPublic},
+	{Method: "POST", Path: "/collections", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	{Method: "GET", Path: "/collections/:id", Access: middleware.AccessPublic},
+	{Method: "DELETE", Path: "/collections/:id", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesDelete},
+	{Method: "POST", Path: "/collections/:id/movies", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+	// 从合集移除电影属于编辑合集，不删除目录数据
+	{Method: "DELETE", Path: "/collections/:id/movies/:title", Access: middleware.AccessAuthenticated, Permission: models.PermMoviesWrite},
+
+	// 全量导出包含评分者ID，不公开
+	{Method: "GET", Path: "/export/movies", Access: middleware.AccessAuthenticated, Permission: models.PermDataExport},
+	{Method: "GET", Path: "/export/ratings", Access: middleware.AccessAuthenticated, Permission: models.PermDataExport},
+
+	{Method: "POST", Path: "/api-keys", Access: middleware.AccessAuthenticated, Permission: models.PermKeysManage},
+	{Method: "GET", Path: "/api-keys", Access: middleware.AccessAuthenticated, Permission: models.PermKeysManage},
+	{Method: "DELETE", Path: "/api-keys/:id", Access: middleware.AccessAuthenticated, Permission: models.PermKeysManage},
+
+	{Method: "GET", Path: "/rating-flags", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsModerate},
+	{Method: "POST", Path: "/rating-flags/:id/confirm", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsModerate},
+	{Method: "POST", Path: "/rating-flags/:id/clear", Access: middleware.AccessAuthenticated, Permission: models.PermRatingsModerate},
+
+	{Method: "GET", Path: "/roles", Access: middleware.AccessAuthenticated, Permission: models.PermRolesManage},
+	{Method: "GET", Path: "/users/:subject/roles", Access: middleware.AccessAuthenticated, Permission: models.PermRolesManage},
+	{Method: "PUT", Path: "/users/:subject/roles/:role", Access: middleware.AccessAuthenticated, Permission: models.PermRolesManage},
+	{Method: "DELETE", Path: "/users/:subject/roles/:role", Access: middleware.AccessAuthenticated, Permission: models.PermRolesManage},
+}
+
+// runPrintRoutePolicy 打印路由授权策略子命令，每行一条规则：
+// 方法|路由模板|访问级别|所需权限|权限要求的凭证范围，供e2e测试生成权限矩阵。
+// 用法: api route-policy
+func runPrintRoutePolicy(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: api route-policy")
+		return 2
+	}
+
+	for _, rule := range routePolicyRules {
+		fmt.Printf("%s|%s|%s|%s|%s\n", rule.Method, rule.Path, rule.Access, rule.Permission, models.PermissionScopes[rule.Permission])
+	}
+	return 0
+}
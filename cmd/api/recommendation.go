@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"movie-rating-api/internal/repository"
+	"movie-rating-api/internal/service"
+)
+
+// runEvaluateRecommendations 推荐模型评估子命令，留出部分现有评分并报告模型在其上的RMSE。
+// 用法: api evaluate-recommendations [-holdout 0.2] [-seed 1]
+func runEvaluateRecommendations(args []string) int {
+	fs := flag.NewFlagSet("evaluate-recommendations", flag.ContinueOnError)
+	holdout := fs.Float64("holdout", 0.2, "fraction of ratings held out for testing")
+	seed := fs.Int64("seed", 1, "random seed for the split and model initialization")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: api evaluate-recommendations [-holdout 0.2] [-seed 1]")
+		return 2
+	}
+
+	db := initDatabase()
+	defer db.Close()
+
+	recommendationService := service.NewRecommendationService(
+		repository.NewRecommendationRepository(db),
+		repository.NewSimilarityRepository(db),
+		service.DefaultRecommendationConfig(),
+	)
+
+	evaluation, err := recommendationService.Evaluate(*holdout, *seed)
+	if err != nil {
+		log.Printf("Evaluation failed: %v", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(evaluation); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return 1
+	}
+
+	log.Printf("Evaluation finished: RMSE %.4f (baseline %.4f) on %d held-out ratings",
+		evaluation.RMSE, evaluation.BaselineRMSE, evaluation.TestRatings)
+	return 0
+}
+
+// startRecommendationTrainer 启动时训练一次推荐模型，之后按间隔重新训练；interval不大于0时不启动
+func startRecommendationTrainer(recommendationService service.RecommendationService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	train := func() {
+		start := time.Now()
+		count, err := recommendationService.Train()
+		if err != nil {
+			log.Printf("Failed to train recommendation model: %v", err)
+			return
+		}
+		log.Printf("Trained recommendation model on %d ratings in %s", count, time.Since(start).Round(time.Millisecond))
+	}
+
+	go func() {
+		train()
+		for range time.Tick(interval) {
+			train()
+		}
+	}()
+}
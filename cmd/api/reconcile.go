@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"movie-rating-api/internal/repository"
+	"movie-rating-api/internal/service"
+)
+
+// runReconcileStats 评分聚合对账子命令，从评分表重新计算聚合并报告与物化表的差异。
+// 用法: api reconcile-stats [-repair]
+// 返回进程退出码：发现差异且未修复时返回1
+func runReconcileStats(args []string) int {
+	fs := flag.NewFlagSet("reconcile-stats", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "overwrite drifted aggregates with recomputed values")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: api reconcile-stats [-repair]")
+		return 2
+	}
+
+	db := initDatabase()
+	defer db.Close()
+
+	statsService := service.NewRatingStatsService(repository.NewRatingStatsRepository(db))
+	report, err := statsService.Reconcile(*repair)
+	if err != nil {
+		log.Printf("Reconciliation failed: %v", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return 1
+	}
+
+	log.Printf("Reconciliation finished: %d drifted, repaired=%t", report.Drifted, report.Repaired)
+	if report.Drifted > 0 && !report.Repaired {
+		return 1
+	}
+	return 0
+}
+
+// startStatsReconciler 定期对账并修复物化评分聚合，interval不大于0时不启动
+func startStatsReconciler(statsService service.RatingStatsService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			report, err := statsService.Reconcile(true)
+			if err != nil {
+				log.Printf("Rating stats reconciliation failed: %v", err)
+				continue
+			}
+			for _, drift := range report.Items {
+				log.Printf("Repaired rating stats drift for %q: stored %d/%.1f, actual %d/%.1f",
+					drift.MovieTitle, drift.StoredCount, drift.StoredSum, drift.ActualCount, drift.ActualSum)
+			}
+		}
+	}()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"movie-rating-api/internal/service"
+)
+
+// startSimilarityJob 启动时计算一次电影相似度，之后按间隔重算；interval不大于0时不启动
+func startSimilarityJob(similarityService service.SimilarityService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	compute := func() {
+		start := time.Now()
+		count, err := similarityService.ComputeSimilarities()
+		if err != nil {
+			log.Printf("Failed to compute movie similarities: %v", err)
+			return
+		}
+		log.Printf("Computed %d movie similarities in %s", count, time.Since(start).Round(time.Millisecond))
+	}
+
+	go func() {
+		compute()
+		for range time.Tick(interval) {
+			compute()
+		}
+	}()
+}
@@ -2,7 +2,8 @@
 
 # E2E Test Script for Movies API
 # Usage: ./e2e-test.sh
-# Prerequisites: curl, jq, bash
+# Prerequisites: curl, jq, bash (plus go or ROUTE_POLICY_CMD / EVALUATE_RECOMMENDATIONS_CMD for the permission matrix and
+# recommendation evaluation, openssl when JWT_HS256_SECRET is set)
 # Default service URL: http://127.0.0.1:8080
 
 # Load environment variables from .env file if it exists
@@ -66,11 +67,10 @@ make_request() {
     
     if [[ -n "$headers" ]]; then
         # Parse headers and add them to curl_args
-        if [[ "$headers" == *"-H"* ]]; then
-            # Extract header value from -H 'value' format
-            header_value=$(echo "$headers" | sed "s/^-H '//" | sed "s/'$//")
-            curl_args+=(-H "$header_value")
-        fi
+        # Extract each header value from -H 'value' format
+        while IFS= read -r header_value; do
+            [[ -n "$header_value" ]] && curl_args+=(-H "$header_value")
+        done < <(echo "$headers" | grep -o "\-H '[^']*'" | sed "s/^-H '//" | sed "s/'$//")
     fi
     
     if [[ -n "$data" ]]; then
@@ -230,7 +230,7 @@ stage3_rating_system() {
     rating_data='{"rating": 4.5}'
     
     # Try 201 first (new rating), then 200 (upsert)
-    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user123'" "$rating_data" 201) 2>/dev/null; then
+    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user123'" "$rating_data" 201) 2>/dev/null; then
         rater_id=$(echo "$response" | jq -r '.raterId')
         rating_value=$(echo "$response" | jq -r '.rating')
         if [[ "$rater_id" == "user123" && "$rating_value" == "4.5" ]]; then
@@ -238,7 +238,7 @@ stage3_rating_system() {
         else
             log_error "Rating response incorrect - raterId: $rater_id, rating: $rating_value"
         fi
-    elif response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user123'" "$rating_data" 200) 2>/dev/null; then
+    elif response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user123'" "$rating_data" 200) 2>/dev/null; then
         rater_id=$(echo "$response" | jq -r '.raterId')
         rating_value=$(echo "$response" | jq -r '.rating')
         if [[ "$rater_id" == "user123" && "$rating_value" == "4.5" ]]; then
@@ -254,7 +254,7 @@ stage3_rating_system() {
     log_info "Updating existing rating for 'Test Movie 1'..."
     updated_rating_data='{"rating": 3.5}'
     
-    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user123'" "$updated_rating_data" 200); then
+    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user123'" "$updated_rating_data" 200); then
         rating_value=$(echo "$response" | jq -r '.rating')
         if [[ "$rating_value" == "3.5" ]]; then
             log_success "Rating updated successfully (Upsert semantics working)"
@@ -268,9 +268,9 @@ stage3_rating_system() {
     # Add another rating from different user
     log_info "Adding rating from different user..."
     # Try 201 first (new rating), then 200 (upsert)
-    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user456'" '{"rating": 4.0}' 201) 2>/dev/null; then
+    if response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user456'" '{"rating": 4.0}' 201) 2>/dev/null; then
         log_success "Second rating added successfully (201)"
-    elif response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user456'" '{"rating": 4.0}' 200) 2>/dev/null; then
+    elif response=$(make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user456'" '{"rating": 4.0}' 200) 2>/dev/null; then
         log_success "Second rating upserted successfully (200)"
     else
         log_error "Failed to add second rating - unexpected status code"
@@ -440,7 +440,7 @@ stage6_error_handling() {
     
     # Test rating submission for non-existent movie (should return 404)
     log_info "Testing rating submission for non-existent movie (expecting 404)..."
-    if make_request "POST" "/movies/NonExistentMovie/ratings" "-H 'X-Rater-Id: user123'" '{"rating":4.0}' 404 >/dev/null; then
+    if make_request "POST" "/movies/NonExistentMovie/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user123'" '{"rating":4.0}' 404 >/dev/null; then
         log_success "Correctly returned 404 for rating submission to non-existent movie"
     else
         log_error "Should return 404 for rating submission to non-existent movie"
@@ -448,13 +448,13 @@ stage6_error_handling() {
     
     # Test invalid rating values (should return 422)
     log_info "Testing invalid rating value (expecting 422)..."
-    if make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user999'" '{"rating":6.0}' 422 >/dev/null; then
+    if make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user999'" '{"rating":6.0}' 422 >/dev/null; then
         log_success "Correctly returned 422 for invalid rating value"
     else
         log_error "Should return 422 for invalid rating value"
     fi
     
-    if make_request "POST" "/movies/Test Movie 1/ratings" "-H 'X-Rater-Id: user999'" '{"rating":0.25}' 422 >/dev/null; then
+    if make_request "POST" "/movies/Test Movie 1/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: user999'" '{"rating":0.25}' 422 >/dev/null; then
         log_success "Correctly returned 422 for invalid rating step"
     else
         log_error "Should return 422 for invalid rating step"
@@ -485,6 +485,616 @@ stage6_error_handling() {
     fi
 }
 
+# Stage 7: Role-based permission matrix
+# Returns only the HTTP status code of a request made with the given bearer token
+request_status() {
+    local method=$1
+    local url=$2
+    local token=$3
+    local data=${4:-}
+
+    local curl_args=(-s -o /dev/null -w "%{http_code}" -X "$method" --connect-timeout $TIMEOUT)
+    if [[ -n "$token" ]]; then
+        curl_args+=(-H "Authorization: Bearer $token")
+    fi
+    if [[ -n "$data" ]]; then
+        curl_args+=(-H "Content-Type: application/json" -d "$data")
+    fi
+
+    curl "${curl_args[@]}" "$BASE_URL$(echo "$url" | sed 's/ /%20/g')" 2>/dev/null || echo "000"
+}
+
+# Creates an API key with the given scopes (JSON array) and subject, prints the plaintext key
+create_api_key() {
+    local name=$1
+    local scopes=$2
+    local subject=$3
+
+    curl -s -X POST --connect-timeout $TIMEOUT \
+        -H "Authorization: Bearer $AUTH_TOKEN" -H "Content-Type: application/json" \
+        -d "{\"name\":\"$name\",\"scopes\":$scopes,\"subject\":\"$subject\"}" \
+        "$BASE_URL/api-keys" | jq -r '.key // empty'
+}
+
+# Signs a JWT with HS256: header and payload are JSON documents
+sign_jwt() {
+    local header_json=$1
+    local payload_json=$2
+    local secret=$3
+    local header payload signature
+
+    b64url() { openssl base64 -A | tr '+/' '-_' | tr -d '='; }
+    header=$(printf '%s' "$header_json" | b64url)
+    payload=$(printf '%s' "$payload_json" | b64url)
+    signature=$(printf '%s' "$header.$payload" | openssl dgst -sha256 -hmac "$secret" -binary | b64url)
+
+    echo "$header.$payload.$signature"
+}
+
+# Prints the claims of a valid token for the given subject: expires in 10 minutes,
+# with iss/aud matching JWT_ISSUER/JWT_AUDIENCE when configured
+jwt_claims() {
+    local subject=$1
+    local now
+    now=$(date +%s)
+
+    jq -cn --arg sub "$subject" --argjson iat "$now" --argjson exp "$((now + 600))" \
+        --arg iss "${JWT_ISSUER:-}" --arg aud "${JWT_AUDIENCE:-}" \
+        '{sub: $sub, iat: $iat, exp: $exp} + (if $iss != "" then {iss: $iss} else {} end) + (if $aud != "" then {aud: $aud} else {} end)'
+}
+
+# Mints a short-lived HS256 JWT for the given subject using JWT_HS256_SECRET
+mint_jwt() {
+    sign_jwt '{"alg":"HS256","typ":"JWT"}' "$(jwt_claims "$1")" "$JWT_HS256_SECRET"
+}
+
+# Substitutes route template parameters with concrete values; /raters/:id uses the caller's own subject
+route_path() {
+    local path=$1
+    local subject=$2
+
+    path=${path//:title/Test Movie 1}
+    path=${path//:region/GB}
+    path=${path//:channel/theatrical}
+    path=${path//:system/BBFC}
+    path=${path//:chart/top-rated}
+    path=${path//:reaction/like}
+    if [[ "$path" == /raters/:id* ]]; then
+        path=${path/:id/$subject}
+    fi
+    path=${path//:id/0}
+    echo "$path" | sed -E 's#:[A-Za-z]+#none#g'
+}
+
+# Expected outcome of an authenticated route for a credential: allow when one of its roles
+# grants the permission and its scopes ("*" = unrestricted) cover the required scope, else 403
+expected_status() {
+    local permission=$1
+    local scope=$2
+    local roles=$3
+    local scopes=$4
+    local role granted=false
+
+    if [[ -z "$permission" ]]; then
+        echo "allow"
+        return
+    fi
+    for role in $roles; do
+        if [[ " ${ROLE_PERMISSIONS[$role]} " == *" $permission "* ]]; then
+            granted=true
+        fi
+    done
+    if [[ "$granted" != true ]]; then
+        echo "403"
+        return
+    fi
+    if [[ -n "$scope" && "$scopes" != "*" && " $scopes " != *" $scope "* && " $scopes " != *" admin "* ]]; then
+        echo "403"
+        return
+    fi
+    echo "allow"
+}
+
+# Compares a status with an expectation (allow = neither 401 nor 403)
+check_status() {
+    local label=$1
+    local expected=$2
+    local status=$3
+
+    if [[ "$expected" == "allow" ]]; then
+        if [[ "$status" != "401" && "$status" != "403" && "$status" != "000" ]]; then
+            log_success "$label allowed ($status)"
+        else
+            log_error "$label should be allowed, got $status"
+        fi
+    elif [[ "$status" == "$expected" ]]; then
+        log_success "$label denied ($status)"
+    else
+        log_error "$label expected $expected, got $status"
+    fi
+}
+
+stage7_permission_matrix() {
+    echo -e "\n${BLUE}=== STAGE 7: Role-based Permission Matrix ===${NC}"
+
+    # Every entry of routePolicyRules: method|path|access|permission|scope
+    local policy
+    if ! policy=$(${ROUTE_POLICY_CMD:-go run ./cmd/api route-policy} 2>/dev/null) || [[ -z "$policy" ]]; then
+        log_error "Could not load the route policy (set ROUTE_POLICY_CMD to the api binary's route-policy command)"
+        return
+    fi
+
+    # Permissions granted by each role, as stored in the database
+    declare -gA ROLE_PERMISSIONS=()
+    local role_name role_perms
+    while IFS='|' read -r role_name role_perms; do
+        [[ -n "$role_name" ]] && ROLE_PERMISSIONS[$role_name]="$role_perms"
+    done < <(curl -s --connect-timeout $TIMEOUT -H "Authorization: Bearer $AUTH_TOKEN" "$BASE_URL/roles" |
+        jq -r '.items[] | "\(.name)|\(.permissions | join(" "))"')
+    if [[ ${#ROLE_PERMISSIONS[@]} -eq 0 ]]; then
+        log_error "Could not load role permissions"
+        return
+    fi
+
+    # Every credential with a subject also holds the rater role
+    local rater_key editor_key moderator_token importer_key
+    rater_key=$(create_api_key "e2e-rater" '["ratings:write"]' "e2e-rater")
+    editor_key=$(create_api_key "e2e-editor" '["movies:write","data:export"]' "e2e-editor")
+    importer_key=$(create_api_key "e2e-importer" '["movies:write"]' "e2e-importer")
+    request_status "PUT" "/users/e2e-moderator/roles/moderator" "$AUTH_TOKEN" >/dev/null
+
+    local moderator_scopes
+    if [[ -n "${JWT_HS256_SECRET:-}" ]]; then
+        moderator_token=$(mint_jwt "e2e-moderator")
+        moderator_scopes="*"
+    else
+        log_warning "JWT_HS256_SECRET not set; moderator uses a ratings:write API key, so moderation is expected to be denied"
+        moderator_token=$(create_api_key "e2e-moderator" '["ratings:write"]' "e2e-moderator")
+        moderator_scopes="ratings:write"
+    fi
+    if [[ -z "$rater_key" || -z "$editor_key" || -z "$importer_key" || -z "$moderator_token" ]]; then
+        log_error "Could not create credentials for the permission matrix"
+        return
+    fi
+
+    local roles=(anonymous rater editor moderator admin)
+    local -A tokens=([anonymous]="" [rater]="$rater_key" [editor]="$editor_key" [moderator]="$moderator_token" [admin]="$AUTH_TOKEN")
+    local -A subjects=([anonymous]="e2e-rater" [rater]="e2e-rater" [editor]="e2e-editor" [moderator]="e2e-moderator" [admin]="e2e-rater")
+    local -A credential_roles=([rater]="rater" [editor]="editor rater" [moderator]="moderator rater" [admin]="admin")
+    local -A credential_scopes=([rater]="ratings:write" [editor]="movies:write data:export" [moderator]="$moderator_scopes" [admin]="*")
+
+    local method template access permission scope path body role expected status
+    while IFS='|' read -r method template access permission scope; do
+        body=""
+        if [[ "$method" == "POST" || "$method" == "PUT" ]]; then
+            body="{}"
+        fi
+        for role in "${roles[@]}"; do
+            path=$(route_path "$template" "${subjects[$role]}")
+            if [[ "$access" == "public" ]]; then
+                expected="allow"
+            elif [[ "$role" == "anonymous" ]]; then
+                expected="401"
+            else
+                expected=$(expected_status "$permission" "$scope" "${credential_roles[$role]}" "${credential_scopes[$role]}")
+            fi
+            status=$(request_status "$method" "$path" "${tokens[$role]}" "$body")
+            check_status "$method $path as $role" "$expected" "$status"
+        done
+    done <<< "$policy"
+
+    # Checks enforced in handlers rather than by the route policy
+    # method|path|body|anonymous|rater|editor|moderator|admin
+    local extra=(
+        "DELETE|/raters/someone-else/ratings/none||401|403|403|403|allow"
+    )
+    local entry expectations i
+    local -a expectations_list
+    for entry in "${extra[@]}"; do
+        IFS='|' read -r method path body expectations <<< "$entry"
+        IFS='|' read -r -a expectations_list <<< "$expectations"
+        for i in "${!roles[@]}"; do
+            status=$(request_status "$method" "$path" "${tokens[${roles[$i]}]}" "$body")
+            check_status "$method $path as ${roles[$i]}" "${expectations_list[$i]}" "$status"
+        done
+    done
+
+    # A narrowly scoped importer key must not download raw data
+    for path in /export/movies /export/ratings; do
+        check_status "GET $path as movies:write-only key" "403" "$(request_status "GET" "$path" "$importer_key")"
+    done
+}
+
+# Stage 8: JWT verification
+stage8_jwt_verification() {
+    echo -e "\n${BLUE}=== STAGE 8: JWT Verification ===${NC}"
+
+    if [[ -z "${JWT_HS256_SECRET:-}" ]]; then
+        log_warning "JWT_HS256_SECRET not set; skipping JWT verification checks"
+        return
+    fi
+
+    # Clock skew tolerated by the service (JWT_CLOCK_SKEW, seconds only)
+    local skew=60
+    if [[ "${JWT_CLOCK_SKEW:-}" =~ ^([0-9]+)s?$ ]]; then
+        skew=${BASH_REMATCH[1]}
+    fi
+
+    local hs_header='{"alg":"HS256","typ":"JWT"}'
+    local now claims
+    now=$(date +%s)
+    claims=$(jwt_claims "e2e-jwt")
+
+    # label|expected|token (expected: allow or 401)
+    local cases=(
+        "valid token|allow|$(mint_jwt "e2e-jwt")"
+        "token without exp|401|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c 'del(.exp)')" "$JWT_HS256_SECRET")"
+        "token expired beyond clock skew|401|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c --argjson exp "$((now - skew - 60))" '.exp = $exp')" "$JWT_HS256_SECRET")"
+        "token with nbf beyond clock skew|401|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c --argjson nbf "$((now + skew + 60))" '.nbf = $nbf')" "$JWT_HS256_SECRET")"
+        "token with unknown kid|401|$(sign_jwt '{"alg":"HS256","typ":"JWT","kid":"e2e-unknown-kid"}' "$claims" "$JWT_HS256_SECRET")"
+        "token signed with another secret|401|$(sign_jwt "$hs_header" "$claims" "e2e-wrong-secret")"
+        "token with alg none|401|$(sign_jwt '{"alg":"none","typ":"JWT"}' "$claims" "$JWT_HS256_SECRET")"
+        "RS256 token with an HMAC signature|401|$(sign_jwt '{"alg":"RS256","typ":"JWT"}' "$claims" "$JWT_HS256_SECRET")"
+    )
+
+    # Expiry and nbf within the tolerated skew are accepted
+    if [[ $skew -ge 20 ]]; then
+        cases+=(
+            "token expired within clock skew|allow|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c --argjson exp "$((now - skew / 2))" '.exp = $exp')" "$JWT_HS256_SECRET")"
+            "token with nbf within clock skew|allow|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c --argjson nbf "$((now + skew / 2))" '.nbf = $nbf')" "$JWT_HS256_SECRET")"
+        )
+    fi
+
+    if [[ -n "${JWT_ISSUER:-}" ]]; then
+        cases+=("token with wrong issuer|401|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c '.iss = "e2e-wrong-issuer"')" "$JWT_HS256_SECRET")")
+    fi
+    if [[ -n "${JWT_AUDIENCE:-}" ]]; then
+        cases+=("token with wrong audience|401|$(sign_jwt "$hs_header" "$(echo "$claims" | jq -c '.aud = "e2e-wrong-audience"')" "$JWT_HS256_SECRET")")
+    fi
+
+    # Algorithm confusion: the RSA public key must not be accepted as an HMAC secret
+    if [[ -n "${JWT_RS256_PUBLIC_KEY_FILE:-}" && -r "$JWT_RS256_PUBLIC_KEY_FILE" ]]; then
+        cases+=("HS256 token signed with the RSA public key|401|$(sign_jwt "$hs_header" "$claims" "$(cat "$JWT_RS256_PUBLIC_KEY_FILE")")")
+    fi
+
+    local entry label expected token
+    for entry in "${cases[@]}"; do
+        IFS='|' read -r label expected token <<< "$entry"
+        check_status "GET /movies with $label" "$expected" "$(request_status "GET" "/movies" "$token")"
+    done
+}
+
+# Stage 9: Batch import
+# Sends a request whose body is read from stdin; prints the response body, then the status code on its own line
+raw_request() {
+    local method=$1
+    local url=$2
+    local content_type=$3
+
+    curl -s -w "\n%{http_code}" -X "$method" --connect-timeout $TIMEOUT \
+        -H "Authorization: Bearer $AUTH_TOKEN" -H "Content-Type: $content_type" \
+        --data-binary @- "$BASE_URL$url" 2>/dev/null || echo -e "\n000"
+}
+
+stage9_batch_import() {
+    echo -e "\n${BLUE}=== STAGE 9: Batch Import ===${NC}"
+
+    # Titles are unique per run so earlier runs' movies are not reported as skipped
+    local run response body status
+    run=$(date +%s)
+
+    # Best-effort: each row is reported on its own, parse errors do not abort the import
+    log_info "Importing a CSV with valid, invalid, duplicate and malformed rows (best-effort)..."
+    response=$(printf '%s\n' \
+        "title,releaseDate,genre,budget" \
+        "E2E Import A $run,2024-03-01,Drama,1000000" \
+        "E2E Import B $run,2024-03-02,Drama,not-a-number" \
+        "E2E Import A $run,2024-03-01,Drama," \
+        "E2E \"Import\" C $run,2024-03-03,Drama," \
+        "E2E Import D $run,2024-03-04,Drama," |
+        raw_request "POST" "/movies/batchImport?mode=best-effort" "text/csv")
+    body=$(echo "$response" | sed '$d')
+    status=$(echo "$response" | tail -n 1)
+    if [[ "$status" == "200" ]]; then
+        local summary rows
+        summary=$(echo "$body" | jq -c '[.total, .created, .skipped, .failed]')
+        rows=$(echo "$body" | jq -c '[.rows[] | "\(.row):\(.status)"]')
+        if [[ "$summary" == "[5,2,1,2]" ]]; then
+            log_success "Best-effort import report totals are correct: $summary"
+        else
+            log_error "Expected [total, created, skipped, failed] = [5,2,1,2], got $summary"
+        fi
+        if [[ "$rows" == '["2:created","3:failed","4:skipped","5:failed","6:created"]' ]]; then
+            log_success "Rows are reported with their CSV line numbers, the malformed row did not stop the import"
+        else
+            log_error "Unexpected per-row results: $rows"
+        fi
+        if echo "$body" | jq -e '.rows[1].error | test("invalid budget")' >/dev/null; then
+            log_success "Invalid budget is reported on its row"
+        else
+            log_error "Expected an invalid budget error on row 3, got: $(echo "$body" | jq -c '.rows[1]')"
+        fi
+        if echo "$body" | jq -e '.rows[3].error | test("invalid CSV row")' >/dev/null; then
+            log_success "Malformed CSV row is reported on its row"
+        else
+            log_error "Expected an invalid CSV row error on row 5, got: $(echo "$body" | jq -c '.rows[3]')"
+        fi
+    else
+        log_error "Best-effort import expected 200, got $status: $body"
+    fi
+
+    # Atomic: one failing row rolls back the whole batch
+    log_info "Importing a CSV with a failing row (atomic)..."
+    response=$(printf '%s\n' \
+        "title,releaseDate,genre" \
+        "E2E Import E $run,2024-04-01,Drama" \
+        "E2E Import F $run,not-a-date,Drama" |
+        raw_request "POST" "/movies/batchImport" "text/csv")
+    body=$(echo "$response" | sed '$d')
+    status=$(echo "$response" | tail -n 1)
+    if [[ "$status" == "200" && "$(echo "$body" | jq -c '[.created, .skipped, .failed]')" == "[0,1,1]" ]]; then
+        log_success "Atomic import created nothing when a row failed"
+    else
+        log_error "Atomic import with a failing row expected [created, skipped, failed] = [0,1,1], got $status: $body"
+    fi
+    status=$(request_status "GET" "/movies/E2E Import E $run/rating" "")
+    if [[ "$status" == "404" ]]; then
+        log_success "Valid row of the rolled back batch was not created"
+    else
+        log_error "Rolled back movie should not exist, GET returned $status"
+    fi
+
+    # Whole-body errors
+    local cases=(
+        "CSV without a title column|text/csv|400|name,releaseDate"
+        "malformed JSON|application/json|400|[{\"title\": "
+        "unsupported content type|text/plain|415|title"
+    )
+    local entry label content_type expected data
+    for entry in "${cases[@]}"; do
+        IFS='|' read -r label content_type expected data <<< "$entry"
+        status=$(printf '%s\n' "$data" | raw_request "POST" "/movies/batchImport" "$content_type" | tail -n 1)
+        if [[ "$status" == "$expected" ]]; then
+            log_success "Import with $label returned $expected"
+        else
+            log_error "Import with $label expected $expected, got $status"
+        fi
+    done
+
+    # Bodies over the 32 MiB limit are rejected instead of being buffered
+    log_info "Importing a body over the size limit..."
+    status=$({ echo "title"; head -c $((33 << 20)) /dev/zero | tr '\0' 'a'; } |
+        raw_request "POST" "/movies/batchImport" "text/csv" | tail -n 1)
+    if [[ "$status" == "413" ]]; then
+        log_success "Oversized import body returned 413"
+    else
+        log_error "Oversized import body expected 413, got $status"
+    fi
+}
+
+# Stage 10: Average rating rounding
+stage10_rating_rounding() {
+    echo -e "\n${BLUE}=== STAGE 10: Average Rating Rounding ===${NC}"
+
+    # Rounding mode of the service (RATING_ROUNDING)
+    local mode=${RATING_ROUNDING:-half-up}
+    local title
+    title="E2E Rounding $(date +%s)"
+
+    if ! make_request "POST" "/movies" "-H 'Authorization: Bearer $AUTH_TOKEN'" \
+        "{\"title\":\"$title\",\"releaseDate\":\"2024-05-01\",\"genre\":\"Drama\"}" 201 >/dev/null; then
+        log_error "Could not create a movie for the rounding checks"
+        return
+    fi
+
+    # rater|score|expected half-up average|expected half-even average
+    # 4.0 and 4.5 average to the midpoint 4.25; adding 3.0 gives 3.8333, which is not a midpoint
+    local steps=(
+        "e2e-round-1|4.0|4.0|4.0"
+        "e2e-round-2|4.5|4.3|4.2"
+        "e2e-round-3|3.0|3.8|3.8"
+    )
+    local entry rater score half_up half_even expected average
+    for entry in "${steps[@]}"; do
+        IFS='|' read -r rater score half_up half_even <<< "$entry"
+        if ! make_request "POST" "/movies/$title/ratings" "-H 'Authorization: Bearer $AUTH_TOKEN' -H 'X-Rater-Id: $rater'" \
+            "{\"score\": $score}" 201 >/dev/null; then
+            log_error "Could not submit rating $score as $rater"
+            return
+        fi
+
+        expected=$half_up
+        if [[ "$mode" == "half-even" ]]; then
+            expected=$half_even
+        fi
+        average=$(make_request "GET" "/movies/$title/rating" "" "" 200 | jq -r '.average')
+        if jq -en --argjson got "${average:-null}" --argjson want "$expected" '$got == $want' >/dev/null 2>&1; then
+            log_success "Average after rating $score is $average ($mode)"
+        else
+            log_error "Average after rating $score expected $expected ($mode), got $average"
+        fi
+    done
+}
+
+# Stage 11: Recommendations
+stage11_recommendations() {
+    echo -e "\n${BLUE}=== STAGE 11: Recommendations ===${NC}"
+
+    # Offline evaluation: the same seed must give the same split, model and RMSE
+    local evaluate=${EVALUATE_RECOMMENDATIONS_CMD:-go run ./cmd/api evaluate-recommendations}
+    local first second
+    if first=$($evaluate -seed 7 2>/dev/null) && second=$($evaluate -seed 7 2>/dev/null); then
+        if [[ -n "$first" && "$first" == "$second" ]]; then
+            log_success "Evaluation is deterministic for a fixed seed"
+        else
+            log_error "Evaluation with the same seed differs between runs"
+        fi
+
+        local rmse baseline
+        rmse=$(echo "$first" | jq -r '.rmse')
+        baseline=$(echo "$first" | jq -r '.baselineRmse')
+        if jq -en --argjson rmse "$rmse" '$rmse >= 0 and $rmse <= 4.5' >/dev/null 2>&1; then
+            log_success "Held-out RMSE is within the rating scale: $rmse"
+        else
+            log_error "Held-out RMSE should be between 0 and 4.5, got: $rmse"
+        fi
+        if jq -en --argjson rmse "$rmse" --argjson baseline "$baseline" '$rmse <= $baseline' >/dev/null 2>&1; then
+            log_success "Model RMSE $rmse is not worse than the global-mean baseline $baseline"
+        else
+            log_warning "Model RMSE $rmse is worse than the global-mean baseline $baseline (expected with few ratings)"
+        fi
+    else
+        log_warning "Could not run the offline evaluation (set EVALUATE_RECOMMENDATIONS_CMD, or there are too few ratings)"
+    fi
+
+    # Served recommendations: unrated movies only, predicted scores on the rating scale, best first
+    local rater=e2e-round-1 response again
+    if response=$(make_request "GET" "/raters/$rater/recommendations?limit=20" "" "" 200); then
+        local rated
+        rated=$(make_request "GET" "/raters/$rater/ratings?limit=100" "" "" 200 | jq -c '[.items[].movieTitle]')
+        if echo "$response" | jq -e --argjson rated "${rated:-[]}" '[.items[].title | select(IN($rated[]))] | length == 0' >/dev/null; then
+            log_success "Recommendations exclude movies the rater already rated"
+        else
+            log_error "Recommendations include rated movies: $(echo "$response" | jq -c '[.items[].title]')"
+        fi
+        if echo "$response" | jq -e '[.items[].predictedScore] | all(. >= 0.5 and . <= 5) and . == (sort | reverse)' >/dev/null; then
+            log_success "Predicted scores are on the rating scale and sorted best first"
+        else
+            log_error "Unexpected predicted scores: $(echo "$response" | jq -c '[.items[].predictedScore]')"
+        fi
+
+        again=$(make_request "GET" "/raters/$rater/recommendations?limit=20" "" "" 200)
+        if [[ "$(echo "$response" | jq -c '.items')" == "$(echo "$again" | jq -c '.items')" ]]; then
+            log_success "Repeated requests return the same recommendations"
+        else
+            log_error "Repeated requests returned different recommendations"
+        fi
+    else
+        log_error "Failed to get recommendations for $rater"
+    fi
+}
+
+# Stage 12: Rate limiting
+# Runs last: exhausting the failed-authentication budget blocks authenticated requests from this IP for a while
+# Prints the response headers of a request made with the given bearer token, followed by "status: <code>"
+request_headers() {
+    local method=$1
+    local url=$2
+    local token=$3
+    local data=${4:-}
+
+    local curl_args=(-s -o /dev/null -D - -w "status: %{http_code}\n" -X "$method" --connect-timeout $TIMEOUT)
+    if [[ -n "$token" ]]; then
+        curl_args+=(-H "Authorization: Bearer $token")
+    fi
+    if [[ -n "$data" ]]; then
+        curl_args+=(-H "Content-Type: application/json" -d "$data")
+    fi
+
+    curl "${curl_args[@]}" "$BASE_URL$(echo "$url" | sed 's/ /%20/g')" 2>/dev/null | tr -d '\r'
+}
+
+# Value of a header (case-insensitive) in request_headers output
+header_value() {
+    echo "$1" | grep -i "^$2:" | head -n 1 | sed 's/^[^:]*: *//'
+}
+
+stage12_rate_limiting() {
+    echo -e "\n${BLUE}=== STAGE 12: Rate Limiting ===${NC}"
+
+    # Token bucket: a fresh key has a full bucket, every request takes one token, an empty bucket answers 429
+    # until a token is refilled. Invalid rating bodies are rejected after the limiter, so nothing is written
+    local key
+    key=$(create_api_key "e2e-rate-limit" '["ratings:write"]' "e2e-rate-limit-$(date +%s)")
+    if [[ -z "$key" ]]; then
+        log_error "Could not create an API key for the rate limit checks"
+        return
+    fi
+
+    local path="/movies/Test Movie 1/ratings"
+    local headers status limit remaining previous i
+    headers=$(request_headers "POST" "$path" "$key" "{}")
+    limit=$(header_value "$headers" "RateLimit-Limit")
+    previous=$(header_value "$headers" "RateLimit-Remaining")
+    if [[ ! "$limit" =~ ^[0-9]+$ || ! "$previous" =~ ^[0-9]+$ ]]; then
+        log_error "Rate limited route should return RateLimit-Limit and RateLimit-Remaining headers"
+        return
+    fi
+    if [[ $previous -eq $((limit - 1)) ]]; then
+        log_success "First request took one token ($previous of $limit left)"
+    else
+        log_error "First request should leave $((limit - 1)) of $limit tokens, got $previous"
+    fi
+
+    # Tokens refilled while the loop runs allow a few extra requests
+    local decreasing=true
+    for ((i = 1; i <= limit + 10; i++)); do
+        headers=$(request_headers "POST" "$path" "$key" "{}")
+        status=$(header_value "$headers" "status")
+        [[ "$status" == "429" ]] && break
+        remaining=$(header_value "$headers" "RateLimit-Remaining")
+        if [[ ! "$remaining" =~ ^[0-9]+$ || $remaining -ge $((previous + 1)) ]]; then
+            decreasing=false
+        fi
+        previous=$remaining
+    done
+    if [[ "$decreasing" == true ]]; then
+        log_success "RateLimit-Remaining counts down as requests are made"
+    else
+        log_error "RateLimit-Remaining increased between requests"
+    fi
+
+    local retry_after
+    retry_after=$(header_value "$headers" "Retry-After")
+    if [[ "$status" == "429" && "$retry_after" =~ ^[0-9]+$ && $retry_after -ge 1 ]]; then
+        log_success "Empty bucket returned 429 with Retry-After: $retry_after"
+    else
+        log_error "Expected 429 with Retry-After once $limit tokens were used, got $status (Retry-After: ${retry_after:-none})"
+        return
+    fi
+
+    if [[ $retry_after -le 15 ]]; then
+        sleep "$retry_after"
+        status=$(header_value "$(request_headers "POST" "$path" "$key" "{}")" "status")
+        if [[ "$status" != "429" ]]; then
+            log_success "Request after Retry-After was allowed ($status)"
+        else
+            log_error "Request after Retry-After should be allowed, got 429"
+        fi
+    else
+        log_warning "Retry-After of ${retry_after}s is too long to wait for; skipping the refill check"
+    fi
+
+    # Failed authentication is limited per IP (RATE_LIMIT_AUTH_FAILURES); once exhausted, requests with
+    # credentials are rejected before authentication while anonymous requests still pass
+    local failures=30
+    if [[ "${RATE_LIMIT_AUTH_FAILURES:-}" =~ ^([0-9]+)/ ]]; then
+        failures=${BASH_REMATCH[1]}
+    fi
+    for ((i = 0; i <= failures + 10; i++)); do
+        status=$(request_status "GET" "/movies" "mrk_e2e_invalid_key")
+        [[ "$status" == "429" ]] && break
+        if [[ "$status" != "401" ]]; then
+            log_error "Invalid API key expected 401 or 429, got $status"
+            return
+        fi
+    done
+    if [[ "$status" == "429" ]]; then
+        log_success "Repeated failed authentication returned 429 after $i failures"
+    else
+        log_error "Expected 429 after $failures failed authentications, got $status"
+        return
+    fi
+
+    check_status "GET /movies with a valid token after the failures" "429" "$(request_status "GET" "/movies" "$AUTH_TOKEN")"
+    status=$(request_status "GET" "/movies" "")
+    if [[ "$status" == "200" ]]; then
+        log_success "Anonymous requests are not blocked by failed authentication"
+    else
+        log_error "Anonymous GET /movies expected 200, got $status"
+    fi
+}
+
 # Main execution
 main() {
     echo -e "${GREEN}Starting E2E Tests for Movies API${NC}"
@@ -498,6 +1108,12 @@ main() {
     stage4_search_pagination
     stage5_auth_permissions
     stage6_error_handling
+    stage7_permission_matrix
+    stage8_jwt_verification
+    stage9_batch_import
+    stage10_rating_rounding
+    stage11_recommendations
+    stage12_rate_limiting
     
     # Print summary
     echo -e "\n${BLUE}=== TEST SUMMARY ===${NC}"
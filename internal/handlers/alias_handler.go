@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AliasHandler 电影别名处理器
+type AliasHandler struct {
+	movieService service.MovieService
+}
+
+// NewAliasHandler 创建电影别名处理器实例
+func NewAliasHandler(movieService service.MovieService) *AliasHandler {
+	return &AliasHandler{
+		movieService: movieService,
+	}
+}
+
+// ListAliases 获取电影的别名列表
+func (h *AliasHandler) ListAliases(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	aliases, err := h.movieService.ListAliases(movieTitle)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve aliases"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": aliases})
+}
+
+// AddAlias 为电影添加别名或本地化标题
+func (h *AliasHandler) AddAlias(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	var aliasCreate models.MovieAliasCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&aliasCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title and language are required"})
+		return
+	}
+
+	alias, err := h.movieService.AddAlias(movieTitle, &aliasCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid language") || strings.Contains(err.Error(), "is required") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error adding alias: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add alias"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, alias)
+}
+
+// DeleteAlias 删除电影的别名
+func (h *AliasHandler) DeleteAlias(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	aliasID, err := strconv.ParseInt(c.Param("aliasId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alias ID"})
+		return
+	}
+
+	if err := h.movieService.DeleteAlias(movieTitle, aliasID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete alias"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
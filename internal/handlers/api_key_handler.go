@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler API密钥管理处理器
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建API密钥管理处理器实例
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateKey 创建API密钥，响应中的明文密钥只返回这一次
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+
+	var keyCreate models.APIKeyCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&keyCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Key name and scopes are required"})
+		return
+	}
+
+	created, err := h.apiKeyService.CreateKey(&keyCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error creating api key: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create api key"})
+		return
+	}
+
+	c.Header("Location", "/api-keys/"+created.ID)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListKeys 列出API密钥（不含明文和哈希）
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+
+	keys, err := h.apiKeyService.ListKeys()
+	if err != nil {
+		fmt.Printf("Error listing api keys: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve api keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": keys})
+}
+
+// RevokeKey 吊销API密钥
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+
+	if err := h.apiKeyService.RevokeKey(c.Param("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error revoking api key: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke api key"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
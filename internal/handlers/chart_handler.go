@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// chartSorts 榜单及其排序方式
+var chartSorts = map[string]string{
+	"top-rated":  "rating",
+	"most-rated": "ratingCount",
+}
+
+// defaultChartMinRatings 高分榜默认的最少评分数，避免只有一两个评分的电影上榜
+const defaultChartMinRatings = 3
+
+// ChartHandler 榜单处理器
+type ChartHandler struct {
+	movieService service.MovieService
+}
+
+// NewChartHandler 创建榜单处理器实例
+func NewChartHandler(movieService service.MovieService) *ChartHandler {
+	return &ChartHandler{
+		movieService: movieService,
+	}
+}
+
+// GetChart 获取榜单（top-rated或most-rated），支持与电影列表相同的过滤参数
+func (h *ChartHandler) GetChart(c *gin.Context) {
+
+	sortBy, ok := chartSorts[c.Param("chart")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chart not found"})
+		return
+	}
+
+	query := buildMovieQuery(c)
+	query["sort"] = sortBy
+	if _, ok := query["minRatings"]; !ok && sortBy == "rating" {
+		query["minRatings"] = defaultChartMinRatings
+	}
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.movieService.ListMovies(query, limit, c.Query("cursor"))
+	if err != nil {
+		fmt.Printf("Error retrieving chart: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve chart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
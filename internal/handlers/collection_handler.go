@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionHandler 电影合集处理器
+type CollectionHandler struct {
+	collectionService service.CollectionService
+}
+
+// NewCollectionHandler 创建电影合集处理器实例
+func NewCollectionHandler(collectionService service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CreateCollection 创建合集
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+
+	var collectionCreate models.CollectionCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&collectionCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Collection name is required"})
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(&collectionCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "is required") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error creating collection: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+
+	// 设置Location头
+	c.Header("Location", "/collections/"+collection.ID)
+	c.JSON(http.StatusCreated, collection)
+}
+
+// ListCollections 获取合集列表
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+
+	// 分页参数
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.collectionService.ListCollections(limit, c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve collections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetCollection 获取合集详情及聚合数据
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+
+	detail, err := h.collectionService.GetCollection(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// DeleteCollection 删除合集
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+
+	if err := h.collectionService.DeleteCollection(c.Param("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddMovie 将电影加入合集
+func (h *CollectionHandler) AddMovie(c *gin.Context) {
+
+	var memberAdd models.CollectionMemberAdd
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&memberAdd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+
+	detail, err := h.collectionService.AddMovie(c.Param("id"), &memberAdd)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error adding movie to collection: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add movie to collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// RemoveMovie 将电影移出合集
+func (h *CollectionHandler) RemoveMovie(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	if err := h.collectionService.RemoveMovie(c.Param("id"), movieTitle); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove movie from collection"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentRatingHandler 内容分级处理器
+type ContentRatingHandler struct {
+	contentRatingService service.ContentRatingService
+}
+
+// NewContentRatingHandler 创建内容分级处理器实例
+func NewContentRatingHandler(contentRatingService service.ContentRatingService) *ContentRatingHandler {
+	return &ContentRatingHandler{
+		contentRatingService: contentRatingService,
+	}
+}
+
+// ListSystems 列出支持的分级制度及各级别对应的最低年龄
+func (h *ContentRatingHandler) ListSystems(c *gin.Context) {
+
+	systems, err := h.contentRatingService.ListSystems()
+	if err != nil {
+		fmt.Printf("Error listing content rating systems: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve content rating systems"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": systems})
+}
+
+// ListMovieRatings 获取电影在各分级制度下的级别
+func (h *ContentRatingHandler) ListMovieRatings(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	ratings, err := h.contentRatingService.ListMovieRatings(movieTitle)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing content ratings: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve content ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": ratings})
+}
+
+// SetMovieRating 设置电影在某分级制度下的级别，新建时返回201
+func (h *ContentRatingHandler) SetMovieRating(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	var ratingSet models.ContentRatingSet
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&ratingSet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rating is required"})
+		return
+	}
+
+	rating, created, err := h.contentRatingService.SetMovieRating(movieTitle, c.Param("system"), ratingSet.Rating)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "unknown") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error setting content rating: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set content rating"})
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, rating)
+		return
+	}
+	c.JSON(http.StatusOK, rating)
+}
+
+// DeleteMovieRating 删除电影在某分级制度下的级别
+func (h *ContentRatingHandler) DeleteMovieRating(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	if err := h.contentRatingService.DeleteMovieRating(movieTitle, c.Param("system")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error deleting content rating: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete content rating"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 数据导出处理器
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler 创建数据导出处理器实例
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportMovies 以CSV或JSONL流式导出电影、评分聚合和票房数据，支持与列表相同的过滤和排序参数
+func (h *ExportHandler) ExportMovies(c *gin.Context) {
+
+	format, ok := negotiateExportFormat(c)
+	if !ok {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "format must be 'csv' or 'jsonl'"})
+		return
+	}
+
+	query := buildMovieQuery(c)
+	applyMovieSort(c, query)
+	if err := h.exportService.ValidateMovieQuery(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startExport(c, format, "movies")
+	if err := h.exportService.ExportMovies(c.Writer, format, query); err != nil {
+		// 响应头已发送，只能记录错误并中断输出
+		fmt.Printf("Error exporting movies: %v\n", err)
+		c.Abort()
+	}
+}
+
+// ExportRatings 以CSV或JSONL流式导出原始评分，可按movie和rater过滤
+func (h *ExportHandler) ExportRatings(c *gin.Context) {
+
+	format, ok := negotiateExportFormat(c)
+	if !ok {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "format must be 'csv' or 'jsonl'"})
+		return
+	}
+
+	startExport(c, format, "ratings")
+	if err := h.exportService.ExportRatings(c.Writer, format, c.Query("movie"), c.Query("rater")); err != nil {
+		// 响应头已发送，只能记录错误并中断输出
+		fmt.Printf("Error exporting ratings: %v\n", err)
+		c.Abort()
+	}
+}
+
+// negotiateExportFormat 根据format参数或Accept头确定导出格式，默认CSV
+func negotiateExportFormat(c *gin.Context) (string, bool) {
+	switch strings.ToLower(c.Query("format")) {
+	case "csv":
+		return service.ExportFormatCSV, true
+	case "jsonl", "ndjson":
+		return service.ExportFormatJSONL, true
+	case "":
+	default:
+		return "", false
+	}
+
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, "application/x-ndjson"), strings.Contains(accept, "application/jsonl"):
+		return service.ExportFormatJSONL, true
+	case accept == "", strings.Contains(accept, "text/csv"), strings.Contains(accept, "*/*"):
+		return service.ExportFormatCSV, true
+	}
+	return "", false
+}
+
+// startExport 写出流式导出的响应头，并取消服务器的写超时以支持大表导出
+func startExport(c *gin.Context, format, name string) {
+	contentType := "text/csv; charset=utf-8"
+	if format == service.ExportFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", name, format))
+	c.Header("Vary", "Accept")
+	c.Status(http.StatusOK)
+}
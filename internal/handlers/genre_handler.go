@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GenreHandler 电影类型处理器
+type GenreHandler struct {
+	genreService service.GenreService
+}
+
+// NewGenreHandler 创建电影类型处理器实例
+func NewGenreHandler(genreService service.GenreService) *GenreHandler {
+	return &GenreHandler{
+		genreService: genreService,
+	}
+}
+
+// ListGenres 获取类型列表及各类型的电影数量
+func (h *GenreHandler) ListGenres(c *gin.Context) {
+
+	genres, err := h.genreService.ListGenres()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve genres"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": genres})
+}
+
+// CreateGenre 向分类表中添加类型
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+
+	var genreCreate models.GenreCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&genreCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Genre name is required"})
+		return
+	}
+
+	genre, err := h.genreService.CreateGenre(&genreCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "is required") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error creating genre: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create genre"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseAcceptLanguage 解析Accept-Language头，按权重从高到低返回语言标签，
+// 忽略通配符和q=0的条目
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		entries = append(entries, weighted{tag: tag, q: q})
+	}
+
+	// 稳定排序，权重相同时保留客户端给出的顺序
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	languages := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		languages = append(languages, entry.tag)
+	}
+	return languages
+}
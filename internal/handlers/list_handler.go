@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/middleware"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListHandler 评分者电影列表处理器
+type ListHandler struct {
+	listService service.ListService
+}
+
+// NewListHandler 创建评分者电影列表处理器实例
+func NewListHandler(listService service.ListService) *ListHandler {
+	return &ListHandler{
+		listService: listService,
+	}
+}
+
+// CreateList 为当前评分者创建自定义列表
+func (h *ListHandler) CreateList(c *gin.Context) {
+
+	ownerID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	var listCreate models.UserListCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&listCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "List name is required"})
+		return
+	}
+
+	list, err := h.listService.CreateList(ownerID, &listCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error creating list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create list"})
+		return
+	}
+
+	// 设置Location头
+	c.Header("Location", "/lists/"+list.ID)
+	c.JSON(http.StatusCreated, list)
+}
+
+// ListLists 获取公开列表及调用方自己的列表，owner参数限定评分者
+func (h *ListHandler) ListLists(c *gin.Context) {
+
+	// 分页参数
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	viewerID, _ := middleware.GetSubject(c)
+	page, err := h.listService.ListLists(c.Query("owner"), viewerID, limit, c.Query("cursor"))
+	if err != nil {
+		fmt.Printf("Error listing lists: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lists"})
+		return
+	}
+
+	if viewerID != "" {
+		c.Header("Cache-Control", "private")
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetList 获取列表，私有列表只对所有者可见
+func (h *ListHandler) GetList(c *gin.Context) {
+
+	list, ok := h.visibleList(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetWatchlist 获取评分者的待看列表，所有者首次访问时创建
+func (h *ListHandler) GetWatchlist(c *gin.Context) {
+
+	ownerID := c.Param("id")
+	owner := canManageList(c, ownerID)
+
+	list, err := h.listService.GetWatchlist(ownerID, owner)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error retrieving watchlist: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve watchlist"})
+		return
+	}
+
+	// 私有待看列表对其他人表现为不存在
+	if !owner && list.Visibility != models.ListVisibilityPublic {
+		c.JSON(http.StatusNotFound, gin.H{"error": "watchlist not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// UpdateList 更新列表名称、描述或可见性
+func (h *ListHandler) UpdateList(c *gin.Context) {
+
+	if _, ok := h.ownedList(c); !ok {
+		return
+	}
+
+	var listUpdate models.UserListUpdate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&listUpdate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	list, err := h.listService.UpdateList(c.Param("id"), &listUpdate)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error updating list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteList 删除自定义列表
+func (h *ListHandler) DeleteList(c *gin.Context) {
+
+	if _, ok := h.ownedList(c); !ok {
+		return
+	}
+
+	if err := h.listService.DeleteList(c.Param("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error deleting list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete list"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListItems 分页获取列表中的电影
+func (h *ListHandler) ListItems(c *gin.Context) {
+
+	if _, ok := h.visibleList(c); !ok {
+		return
+	}
+
+	// 分页参数
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.listService.ListItems(c.Param("id"), limit, c.Query("cursor"))
+	if err != nil {
+		fmt.Printf("Error listing list items: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve list items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// AddItem 将电影加入列表
+func (h *ListHandler) AddItem(c *gin.Context) {
+
+	if _, ok := h.ownedList(c); !ok {
+		return
+	}
+
+	var itemAdd models.UserListItemAdd
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&itemAdd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+
+	list, err := h.listService.AddItem(c.Param("id"), &itemAdd)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error adding movie to list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add movie to list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RemoveItem 将电影移出列表
+func (h *ListHandler) RemoveItem(c *gin.Context) {
+
+	if _, ok := h.ownedList(c); !ok {
+		return
+	}
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	if err := h.listService.RemoveItem(c.Param("id"), movieTitle); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error removing movie from list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove movie from list"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// visibleList 获取路径中的列表并检查调用方能否查看；不能查看的私有列表按不存在处理。
+// 失败时已写入响应
+func (h *ListHandler) visibleList(c *gin.Context) (*models.UserList, bool) {
+	list, err := h.listService.GetList(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		fmt.Printf("Error retrieving list: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve list"})
+		return nil, false
+	}
+
+	if list.Visibility != models.ListVisibilityPublic {
+		if !canManageList(c, list.OwnerID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "list not found"})
+			return nil, false
+		}
+		c.Header("Cache-Control", "private")
+	}
+	return list, true
+}
+
+// ownedList 获取路径中的列表并检查调用方能否修改：列表所有者，或具有ratings:impersonate权限的管理员。
+// 失败时已写入响应
+func (h *ListHandler) ownedList(c *gin.Context) (*models.UserList, bool) {
+	list, ok := h.visibleList(c)
+	if !ok {
+		return nil, false
+	}
+
+	principal := middleware.GetPrincipal(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication is required"})
+		return nil, false
+	}
+	if principal.Subject != "" && principal.Subject == list.OwnerID {
+		return list, true
+	}
+
+	decision := principal.Decide(models.PermRatingsImpersonate)
+	if !decision.Allowed {
+		middleware.LogDenial(c, principal, decision)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot modify another rater's list"})
+		return nil, false
+	}
+
+	fmt.Printf("Admin %q (%s) managing list %q of rater %q on %s %s\n",
+		principal.Subject, principal.Method, list.ID, list.OwnerID, c.Request.Method, c.Request.URL.Path)
+	return list, true
+}
+
+// canManageList 判断调用方是否为列表所有者或可代评分者操作的管理员，不记录拒绝日志
+func canManageList(c *gin.Context, ownerID string) bool {
+	principal := middleware.GetPrincipal(c)
+	if principal == nil {
+		return false
+	}
+	if principal.Subject != "" && principal.Subject == ownerID {
+		return true
+	}
+	return principal.Decide(models.PermRatingsImpersonate).Allowed
+}
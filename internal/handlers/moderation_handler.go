@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationHandler 评论审核处理器
+type ModerationHandler struct {
+	moderationService service.ReviewModerationService
+}
+
+// NewModerationHandler 创建评论审核处理器实例
+func NewModerationHandler(moderationService service.ReviewModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// ListQueue 分页列出审核队列，默认列出待审核的评论
+func (h *ModerationHandler) ListQueue(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.moderationService.ListQueue(c.Query("status"), limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing moderation queue: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// Decide 修改评论的审核状态并记录审核人和原因
+func (h *ModerationHandler) Decide(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var decision models.ReviewModerationDecision
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status and reason are required"})
+		return
+	}
+
+	review, err := h.moderationService.Decide(id, actorName(c), &decision)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "concurrently"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "is required"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			fmt.Printf("Error moderating review: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ListHistory 获取评论的审核记录
+func (h *ModerationHandler) ListHistory(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.moderationService.ListHistory(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing moderation history: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": events})
+}
@@ -3,16 +3,24 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"movie-rating-api/internal/middleware"
 	"movie-rating-api/internal/models"
 	"movie-rating-api/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
+// 批量导入的请求体上限及读写超时。导入在请求内同步完成，大文件需要比服务器默认15秒更长的时间
+const (
+	maxImportBodyBytes = 32 << 20
+	importTimeout      = 10 * time.Minute
+)
+
 // MovieHandler 电影处理器
 type MovieHandler struct {
 	movieService  service.MovieService
@@ -38,25 +46,17 @@ func (h *MovieHandler) CreateMovie(c *gin.Context) {
 		return
 	}
 
-	// 验证必填字段
-	if movieCreate.Title == "" || movieCreate.ReleaseDate == "" || movieCreate.Genre == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Title, release date and genre are required"})
-		return
-	}
-	
-	// 验证ReleaseDate格式是否正确（YYYY-MM-DD）
-	if _, err := time.Parse("2006-01-02", movieCreate.ReleaseDate); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Release date must be in YYYY-MM-DD format"})
-		return
-	}
-
-	// 创建电影
+	// 创建电影（必填字段、日期格式和类型在服务层统一校验，批量导入复用同一规则）
 	movie, err := h.movieService.CreateMovie(&movieCreate)
 	if err != nil {
 		if strings.Contains(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if isMovieValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		// 记录详细错误信息
 		fmt.Printf("Error creating movie: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create movie: %v", err)})
@@ -72,22 +72,8 @@ func (h *MovieHandler) CreateMovie(c *gin.Context) {
 func (h *MovieHandler) ListMovies(c *gin.Context) {
 
 	// 构建查询参数
-	query := make(map[string]interface{})
-
-	// 关键词搜索
-	if q := c.Query("q"); q != "" {
-		query["q"] = q
-	}
-
-	// 年份过滤
-	if year := c.Query("year"); year != "" {
-		query["year"] = year
-	}
-
-	// 类型过滤
-	if genre := c.Query("genre"); genre != "" {
-		query["genre"] = genre
-	}
+	query := buildMovieQuery(c)
+	applyMovieSort(c, query)
 
 	// 分页参数
 	limit := 10 // 默认值
@@ -102,10 +88,22 @@ func (h *MovieHandler) ListMovies(c *gin.Context) {
 	// 获取电影列表
 	page, err := h.movieService.ListMovies(query, limit, cursor)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid sort") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve movies"})
 		return
 	}
 
+	// 根据Accept-Language返回本地化标题
+	c.Header("Vary", "Accept-Language")
+	if languages := parseAcceptLanguage(c.GetHeader("Accept-Language")); len(languages) > 0 {
+		if err := h.movieService.LocalizeMovies(page.Items, languages); err != nil {
+			fmt.Printf("Error localizing movies: %v\n", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, page)
 }
 
@@ -121,14 +119,10 @@ func (h *MovieHandler) SubmitRating(c *gin.Context) {
 	// 解码URL中的'+'为空格
 	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
 
-	// 获取评分者ID（从查询参数或上下文）
-	raterID := c.Query("raterId")
-	if raterID == "" {
-		// 尝试从请求头获取
-		raterID = c.GetHeader("X-Rater-ID")
-	}
-	if raterID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Rater ID is required"})
+	// 评分者与已认证身份绑定，代他人评分需要管理员显式代操作
+	raterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 
@@ -155,7 +149,7 @@ func (h *MovieHandler) SubmitRating(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
-		if strings.Contains(err.Error(), "rating must be") {
+		if strings.Contains(err.Error(), "rating must be") || strings.Contains(err.Error(), "comment must be") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -167,7 +161,22 @@ func (h *MovieHandler) SubmitRating(c *gin.Context) {
 	c.JSON(http.StatusCreated, result)
 }
 
-// GetMovieRatings 获取电影评分
+// GetMovieRating 获取电影聚合评分。asOf参数按评分事件计算历史时间点的聚合评分；
+// 调用方携带了身份时附带其本人的评分（myRating）
+func (h *MovieHandler) GetMovieRating(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	aggregate, ok := h.movieRatingAggregate(c, movieTitle)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, aggregate)
+}
+
+// GetMovieRatings 获取电影评分（已弃用，由GET /movies/:title/rating取代）
 func (h *MovieHandler) GetMovieRatings(c *gin.Context) {
 
 	// 获取路径参数并进行URL解码
@@ -179,16 +188,228 @@ func (h *MovieHandler) GetMovieRatings(c *gin.Context) {
 	// 解码URL中的'+'为空格
 	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
 
-	// 获取评分
-	aggregate, err := h.ratingService.GetMovieRatings(movieTitle)
+	// 旧路径继续可用，通过Deprecation和Link头提示调用方迁移
+	c.Header("Deprecation", "true")
+	c.Header("Link", fmt.Sprintf("</movies/%s/rating>; rel=\"successor-version\"", url.PathEscape(movieTitle)))
+
+	aggregate, ok := h.movieRatingAggregate(c, movieTitle)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, aggregate)
+}
+
+// movieRatingAggregate 按查询参数计算聚合评分，新旧两个评分端点共用。出错时写入响应并返回false
+func (h *MovieHandler) movieRatingAggregate(c *gin.Context, movieTitle string) (*models.RatingAggregate, bool) {
+	// asOf参数：按评分事件计算历史时间点的聚合评分
+	if asOfStr := c.Query("asOf"); asOfStr != "" {
+		asOf, err := parseAsOf(asOfStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+
+		aggregate, err := h.ratingService.GetMovieRatingsAsOf(movieTitle, asOf)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return nil, false
+			}
+			fmt.Printf("Error retrieving historical ratings: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ratings"})
+			return nil, false
+		}
+		return aggregate, true
+	}
+
+	// 获取评分（excludeFlagged=true时排除待审核的可疑评分）
+	aggregate, err := h.ratingService.GetMovieRatings(movieTitle, c.Query("excludeFlagged") == "true")
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
+			return nil, false
 		}
+		fmt.Printf("Error retrieving rating: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ratings"})
+		return nil, false
+	}
+
+	// 公开端点：调用方携带了身份时附带其本人的评分
+	if subject, ok := middleware.GetSubject(c); ok {
+		rating, err := h.ratingService.GetRaterRating(movieTitle, subject)
+		if err != nil {
+			fmt.Printf("Error retrieving rater rating: %v\n", err)
+		} else if rating != nil {
+			aggregate.MyRating = &rating.Rating
+		}
+		c.Header("Cache-Control", "private")
+	}
+
+	return aggregate, true
+}
+
+// ListRatingEvents 按时间顺序获取电影的评分变更记录
+func (h *MovieHandler) ListRatingEvents(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.ratingService.ListRatingEvents(movieTitle, limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing rating events: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rating events"})
 		return
 	}
 
-	c.JSON(http.StatusOK, aggregate)
+	c.JSON(http.StatusOK, page)
+}
+
+// parseAsOf 解析asOf参数：RFC3339时间，或YYYY-MM-DD日期（包含UTC当天全部事件）
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339, value); err == nil {
+		return asOf, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("asOf must be an RFC3339 timestamp or a YYYY-MM-DD date")
+}
+
+// BatchImport 批量导入电影，支持JSON数组、CSV和JSONL格式
+func (h *MovieHandler) BatchImport(c *gin.Context) {
+
+	format, err := service.DetectImportFormat(c.Query("format"), c.ContentType(), "")
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := models.ImportOptions{
+		Mode:           c.DefaultQuery("mode", models.ImportModeAtomic),
+		FetchBoxOffice: c.Query("boxOffice") == "true",
+	}
+	if opts.Mode != models.ImportModeAtomic && opts.Mode != models.ImportModeBestEffort {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be 'atomic' or 'best-effort'"})
+		return
+	}
+
+	// 放宽本请求的读写超时，请求体超过上限时读取失败并返回413
+	deadline := time.Now().Add(importTimeout)
+	controller := http.NewResponseController(c.Writer)
+	_ = controller.SetReadDeadline(deadline)
+	_ = controller.SetWriteDeadline(deadline)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes)
+
+	report, err := h.movieService.ImportMovies(c.Request.Body, format, opts)
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("import body must be at most %d MiB", maxImportBodyBytes>>20)})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid import body") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error importing movies: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import movies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// isMovieValidationError 判断是否为创建电影请求的校验错误
+func isMovieValidationError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "are required") || strings.Contains(msg, "must be in") ||
+		strings.Contains(msg, "unknown genre") || strings.Contains(msg, "genre is required") ||
+		strings.Contains(msg, "content rating")
+}
+
+// applyMovieSort 写入排序参数：sort=releaseDate|title|rating|ratingCount，order=asc|desc
+func applyMovieSort(c *gin.Context, query map[string]interface{}) {
+	if sortBy := c.Query("sort"); sortBy != "" {
+		query["sort"] = sortBy
+	}
+	if order := c.Query("order"); order != "" {
+		query["order"] = strings.ToLower(order)
+	}
+}
+
+// buildMovieQuery 从请求参数构建电影过滤条件，列表与导出共用
+func buildMovieQuery(c *gin.Context) map[string]interface{} {
+	query := make(map[string]interface{})
+
+	// 关键词搜索
+	if q := c.Query("q"); q != "" {
+		query["q"] = q
+	}
+
+	// 年份过滤，可指定按哪个地区的上映日期计算
+	if year, err := strconv.Atoi(c.Query("year")); err == nil && year > 0 {
+		query["year"] = year
+	}
+	if region := strings.ToUpper(c.Query("region")); models.ValidRegion(region) {
+		query["region"] = region
+	}
+
+	// 类型过滤
+	if genre := c.Query("genre"); genre != "" {
+		query["genre"] = genre
+	}
+
+	// 用户标签过滤
+	if tag := c.Query("tag"); tag != "" {
+		query["tag"] = tag
+	}
+
+	// 发行商过滤
+	if distributor := c.Query("distributor"); distributor != "" {
+		query["distributor"] = distributor
+	}
+
+	// 预算上限过滤
+	if budget, err := strconv.ParseInt(c.Query("budget"), 10, 64); err == nil && budget > 0 {
+		query["budget"] = budget
+	}
+
+	// 最少评分数过滤
+	if minRatings, err := strconv.Atoi(c.Query("minRatings")); err == nil && minRatings > 0 {
+		query["minRatings"] = minRatings
+	}
+
+	// MPA分级过滤
+	if mpaRating := c.Query("mpaRating"); mpaRating != "" {
+		query["mpaRating"] = mpaRating
+	}
+
+	// 适合N岁观看，可限定按某个分级制度判断
+	if age, err := strconv.Atoi(c.Query("suitableForAge")); err == nil && age >= 0 {
+		query["suitableForAge"] = age
+	}
+	if system := c.Query("contentRatingSystem"); system != "" {
+		query["contentRatingSystem"] = strings.ToUpper(system)
+	}
+
+	// 演职人员过滤
+	if person := c.Query("person"); person != "" {
+		query["person"] = person
+		if role := c.Query("role"); role != "" {
+			query["role"] = strings.ToLower(role)
+		}
+	}
+
+	return query
 }
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonHandler 演职人员处理器
+type PersonHandler struct {
+	personService service.PersonService
+}
+
+// NewPersonHandler 创建演职人员处理器实例
+func NewPersonHandler(personService service.PersonService) *PersonHandler {
+	return &PersonHandler{
+		personService: personService,
+	}
+}
+
+// CreatePerson 创建演职人员
+func (h *PersonHandler) CreatePerson(c *gin.Context) {
+
+	var personCreate models.PersonCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&personCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	person, err := h.personService.CreatePerson(&personCreate)
+	if err != nil {
+		if isPersonValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error creating person: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create person"})
+		return
+	}
+
+	// 设置Location头
+	c.Header("Location", "/people/"+person.ID)
+	c.JSON(http.StatusCreated, person)
+}
+
+// ListPeople 获取演职人员列表
+func (h *PersonHandler) ListPeople(c *gin.Context) {
+
+	// 分页参数
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.personService.ListPeople(c.Query("q"), limit, c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve people"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetPerson 获取演职人员详情
+func (h *PersonHandler) GetPerson(c *gin.Context) {
+
+	person, err := h.personService.GetPerson(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve person"})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// UpdatePerson 更新演职人员信息
+func (h *PersonHandler) UpdatePerson(c *gin.Context) {
+
+	var personUpdate models.PersonCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&personUpdate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	person, err := h.personService.UpdatePerson(c.Param("id"), &personUpdate)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if isPersonValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error updating person: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person"})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// DeletePerson 删除演职人员
+func (h *PersonHandler) DeletePerson(c *gin.Context) {
+
+	if err := h.personService.DeletePerson(c.Param("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete person"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFilmography 获取演职人员的作品年表
+func (h *PersonHandler) GetFilmography(c *gin.Context) {
+
+	filmography, err := h.personService.GetFilmography(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve filmography"})
+		return
+	}
+
+	c.JSON(http.StatusOK, filmography)
+}
+
+// ListCredits 获取电影的演职员表
+func (h *PersonHandler) ListCredits(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	credits, err := h.personService.ListCredits(movieTitle)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve credits"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": credits})
+}
+
+// AddCredit 为电影添加署名
+func (h *PersonHandler) AddCredit(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	var creditCreate models.CreditCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&creditCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Person ID and role are required"})
+		return
+	}
+
+	credit, err := h.personService.AddCredit(movieTitle, &creditCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error adding credit: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add credit"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, credit)
+}
+
+// DeleteCredit 删除电影的署名
+func (h *PersonHandler) DeleteCredit(c *gin.Context) {
+
+	movieTitle := c.Param("title")
+	if movieTitle == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Movie title is required"})
+		return
+	}
+	// 解码URL中的'+'为空格
+	movieTitle = strings.ReplaceAll(movieTitle, "+", " ")
+
+	creditID, err := strconv.ParseInt(c.Param("creditId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credit ID"})
+		return
+	}
+
+	if err := h.personService.DeleteCredit(movieTitle, creditID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete credit"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// isPersonValidationError 判断是否为演职人员请求的校验错误
+func isPersonValidationError(err error) bool {
+	return strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "must be in")
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RaterHandler 评分者处理器
+type RaterHandler struct {
+	ratingService         service.RatingService
+	recommendationService service.RecommendationService
+}
+
+// NewRaterHandler 创建评分者处理器实例
+func NewRaterHandler(ratingService service.RatingService, recommendationService service.RecommendationService) *RaterHandler {
+	return &RaterHandler{
+		ratingService:         ratingService,
+		recommendationService: recommendationService,
+	}
+}
+
+// ListRatings 分页获取评分者的评分历史，sort=date|score，order=asc|desc
+func (h *RaterHandler) ListRatings(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.ratingService.ListRaterRatings(c.Param("id"), c.Query("sort"), strings.ToLower(c.Query("order")), limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing rater ratings: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetStats 获取评分者的评分统计
+func (h *RaterHandler) GetStats(c *gin.Context) {
+
+	stats, err := h.ratingService.GetRaterStats(c.Param("id"))
+	if err != nil {
+		fmt.Printf("Error retrieving rater stats: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rater stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// WithdrawRating 评分者撤回对电影的评分，返回撤回后的聚合评分
+func (h *RaterHandler) WithdrawRating(c *gin.Context) {
+
+	raterID := c.Param("id")
+	if status, errMsg := authorizeRaterAccess(c, raterID); errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	aggregate, err := h.ratingService.WithdrawRating(movieTitle, raterID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error withdrawing rating: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw rating"})
+		return
+	}
+
+	c.JSON(http.StatusOK, aggregate)
+}
+
+// maxRecommendationLimit 推荐列表单次返回的最大数量
+const maxRecommendationLimit = 50
+
+// GetRecommendations 获取为评分者推荐的电影，支持与电影列表相同的过滤参数（如genre、year）
+func (h *RaterHandler) GetRecommendations(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	if limit > maxRecommendationLimit {
+		limit = maxRecommendationLimit
+	}
+
+	recommendations, err := h.recommendationService.Recommend(c.Param("id"), buildMovieQuery(c), limit)
+	if err != nil {
+		fmt.Printf("Error generating recommendations: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recommendations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
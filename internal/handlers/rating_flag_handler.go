@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RatingFlagHandler 可疑评分审核处理器
+type RatingFlagHandler struct {
+	fraudService service.FraudService
+}
+
+// NewRatingFlagHandler 创建可疑评分审核处理器实例
+func NewRatingFlagHandler(fraudService service.FraudService) *RatingFlagHandler {
+	return &RatingFlagHandler{
+		fraudService: fraudService,
+	}
+}
+
+// ListFlags 分页列出审核队列，默认只列出待审核的标记
+func (h *RatingFlagHandler) ListFlags(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	status := c.DefaultQuery("status", models.FlagStatusPending)
+	if status == "all" {
+		status = ""
+	}
+
+	page, err := h.fraudService.ListFlags(status, limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing rating flags: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rating flags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ConfirmFlag 确认标记的评分为刷分，该评分不再计入聚合
+func (h *RatingFlagHandler) ConfirmFlag(c *gin.Context) {
+	h.reviewFlag(c, models.FlagStatusConfirmed)
+}
+
+// ClearFlag 清除标记，评分恢复正常计入
+func (h *RatingFlagHandler) ClearFlag(c *gin.Context) {
+	h.reviewFlag(c, models.FlagStatusCleared)
+}
+
+// reviewFlag 更新标记的审核状态并记录审核人
+func (h *RatingFlagHandler) reviewFlag(c *gin.Context, status string) {
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "rating flag not found"})
+		return
+	}
+
+	flag, err := h.fraudService.ReviewFlag(id, status, actorName(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error reviewing rating flag: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review rating flag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
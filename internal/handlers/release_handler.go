@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReleaseHandler 地区上映记录处理器
+type ReleaseHandler struct {
+	releaseService service.ReleaseService
+}
+
+// NewReleaseHandler 创建地区上映记录处理器实例
+func NewReleaseHandler(releaseService service.ReleaseService) *ReleaseHandler {
+	return &ReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// ListReleases 获取电影在各地区各渠道的上映记录
+func (h *ReleaseHandler) ListReleases(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	releases, err := h.releaseService.ListReleases(movieTitle)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing releases: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve releases"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": releases})
+}
+
+// SetRelease 设置电影在某地区某渠道的上映日期，新建时返回201
+func (h *ReleaseHandler) SetRelease(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	var releaseUpsert models.MovieReleaseUpsert
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&releaseUpsert); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "releaseDate is required"})
+		return
+	}
+
+	release, created, err := h.releaseService.SetRelease(movieTitle, c.Param("region"), c.Param("channel"), &releaseUpsert)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error setting release: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set release"})
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, release)
+		return
+	}
+	c.JSON(http.StatusOK, release)
+}
+
+// DeleteRelease 删除电影在某地区某渠道的上映记录
+func (h *ReleaseHandler) DeleteRelease(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	if err := h.releaseService.DeleteRelease(movieTitle, c.Param("region"), c.Param("channel")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error deleting release: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete release"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCalendar 获取上映日历：from到to之间（默认今天起30天）的上映记录，
+// 可按region和channel过滤
+func (h *ReleaseHandler) GetCalendar(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.releaseService.GetCalendar(c.Query("from"), c.Query("to"), c.Query("region"), c.Query("channel"), limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error retrieving release calendar: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve release calendar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
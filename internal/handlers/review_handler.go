@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/middleware"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 评论处理器
+type ReviewHandler struct {
+	reviewService     service.ReviewService
+	moderationService service.ReviewModerationService
+}
+
+// NewReviewHandler 创建评论处理器实例
+func NewReviewHandler(reviewService service.ReviewService, moderationService service.ReviewModerationService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService:     reviewService,
+		moderationService: moderationService,
+	}
+}
+
+// ListReviews 分页获取电影的评论，sort=recent|helpful
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	viewerID, _ := middleware.GetSubject(c)
+	page, err := h.reviewService.ListReviews(movieTitle, c.Query("sort"), viewerID, limit, c.Query("cursor"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing reviews: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reviews"})
+		return
+	}
+
+	// 响应中包含调用方自己的投票
+	if viewerID != "" {
+		c.Header("Cache-Control", "private")
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetReview 获取评论，未公开的评论只对作者和版主可见
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := middleware.GetSubject(c)
+	moderator := false
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		moderator = principal.Decide(models.PermReviewsModerate).Allowed
+	}
+	review, err := h.reviewService.GetReview(id, viewerID, moderator)
+	if err != nil {
+		h.writeError(c, err, "Failed to retrieve review")
+		return
+	}
+
+	if viewerID != "" {
+		c.Header("Cache-Control", "private")
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// Vote 对评论投有用或无用票
+func (h *ReviewHandler) Vote(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	voterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	var vote models.ReviewVote
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&vote); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "helpful must be true or false"})
+		return
+	}
+
+	review, err := h.reviewService.Vote(id, voterID, *vote.Helpful)
+	if err != nil {
+		h.writeError(c, err, "Failed to record vote")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// RemoveVote 撤回对评论的投票
+func (h *ReviewHandler) RemoveVote(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	voterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.reviewService.RemoveVote(id, voterID); err != nil {
+		h.writeError(c, err, "Failed to remove vote")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddReaction 对评论添加表情回应
+func (h *ReviewHandler) AddReaction(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	raterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	review, err := h.reviewService.AddReaction(id, raterID, c.Param("reaction"))
+	if err != nil {
+		h.writeError(c, err, "Failed to add reaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// RemoveReaction 移除对评论的表情回应
+func (h *ReviewHandler) RemoveReaction(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	raterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.reviewService.RemoveReaction(id, raterID, c.Param("reaction")); err != nil {
+		h.writeError(c, err, "Failed to remove reaction")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReportReview 举报评论
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	reporterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	var reportCreate models.ReviewReportCreate
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&reportCreate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	report, err := h.moderationService.ReportReview(id, reporterID, &reportCreate)
+	if err != nil {
+		if strings.Contains(err.Error(), "already reported") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		h.writeError(c, err, "Failed to report review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// writeError 将评论服务的错误映射为HTTP响应
+func (h *ReviewHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "your own review"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		fmt.Printf("%s: %v\n", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// parseReviewID 解析路径中的评论ID，失败时已写入响应
+func parseReviewID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler 角色管理处理器
+type RoleHandler struct {
+	authorizationService service.AuthorizationService
+}
+
+// NewRoleHandler 创建角色管理处理器实例
+func NewRoleHandler(authorizationService service.AuthorizationService) *RoleHandler {
+	return &RoleHandler{
+		authorizationService: authorizationService,
+	}
+}
+
+// ListRoles 列出角色及其权限
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+
+	roles, err := h.authorizationService.ListRoles()
+	if err != nil {
+		fmt.Printf("Error listing roles: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": roles})
+}
+
+// ListUserRoles 列出主体被授予的角色
+func (h *RoleHandler) ListUserRoles(c *gin.Context) {
+
+	assignments, err := h.authorizationService.ListSubjectRoles(c.Param("subject"))
+	if err != nil {
+		fmt.Printf("Error listing role assignments: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve role assignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": assignments})
+}
+
+// AssignRole 授予主体角色（幂等）
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+
+	assignment, err := h.authorizationService.AssignRole(c.Param("subject"), c.Param("role"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "is required") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error assigning role: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// RevokeRole 撤销主体的角色
+func (h *RoleHandler) RevokeRole(c *gin.Context) {
+
+	if err := h.authorizationService.RevokeRole(c.Param("subject"), c.Param("role")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error revoking role: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke role"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
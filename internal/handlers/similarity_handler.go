@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSimilarLimit 相似电影单次返回的最大数量，与每部电影保存的相似电影数一致
+const maxSimilarLimit = 20
+
+// SimilarityHandler 相似电影处理器
+type SimilarityHandler struct {
+	similarityService service.SimilarityService
+}
+
+// NewSimilarityHandler 创建相似电影处理器实例
+func NewSimilarityHandler(similarityService service.SimilarityService) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarityService: similarityService,
+	}
+}
+
+// ListSimilar 获取与电影相似的电影（看过这部电影的人也喜欢）
+func (h *SimilarityHandler) ListSimilar(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+
+	movies, err := h.similarityService.GetSimilarMovies(movieTitle, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error retrieving similar movies: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve similar movies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": movies})
+}
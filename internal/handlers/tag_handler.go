@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"movie-rating-api/internal/middleware"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxMovieTagsLimit 单部电影最多返回的标签数
+const maxMovieTagsLimit = 50
+
+// maxTrendingDays 热门标签统计窗口的最大天数
+const maxTrendingDays = 90
+
+// TagHandler 电影标签处理器
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler 创建电影标签处理器实例
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// ListMovieTags 获取电影上添加人数最多的标签
+func (h *TagHandler) ListMovieTags(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	if limit > maxMovieTagsLimit {
+		limit = maxMovieTagsLimit
+	}
+
+	viewerID, _ := middleware.GetSubject(c)
+	tags, err := h.tagService.ListMovieTags(movieTitle, viewerID, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing movie tags: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	// 响应中包含调用方是否添加过各标签
+	if viewerID != "" {
+		c.Header("Cache-Control", "private")
+	}
+	c.JSON(http.StatusOK, gin.H{"items": tags})
+}
+
+// AddTag 为电影添加标签，重复添加同一标签时返回200
+func (h *TagHandler) AddTag(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+
+	raterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	var tagAdd models.MovieTagAdd
+
+	// 绑定请求体
+	if err := c.ShouldBindJSON(&tagAdd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag is required"})
+		return
+	}
+
+	tag, added, err := h.tagService.AddTag(movieTitle, raterID, tagAdd.Tag)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "at most") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error adding movie tag: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add tag"})
+		return
+	}
+
+	if !added {
+		c.JSON(http.StatusOK, tag)
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+// RemoveTag 撤回自己为电影添加的标签
+func (h *TagHandler) RemoveTag(c *gin.Context) {
+
+	// 解码URL中的'+'为空格
+	movieTitle := strings.ReplaceAll(c.Param("title"), "+", " ")
+	tag := strings.ReplaceAll(c.Param("tag"), "+", " ")
+
+	raterID, status, errMsg := resolveRaterID(c)
+	if errMsg != "" {
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	if err := h.tagService.RemoveTag(movieTitle, raterID, tag); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error removing movie tag: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove tag"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListPopularTags 分页获取最常用的标签，q为标签前缀（用于输入补全）
+func (h *TagHandler) ListPopularTags(c *gin.Context) {
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	page, err := h.tagService.ListPopularTags(c.Query("q"), limit, c.Query("cursor"))
+	if err != nil {
+		fmt.Printf("Error listing popular tags: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListTrendingTags 获取最近days天（默认7天）添加次数最多的标签
+func (h *TagHandler) ListTrendingTags(c *gin.Context) {
+
+	days := 7 // 默认值
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsedDays, err := strconv.Atoi(daysStr)
+		if err != nil || parsedDays < 1 || parsedDays > maxTrendingDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 1 and %d", maxTrendingDays)})
+			return
+		}
+		days = parsedDays
+	}
+
+	limit := 10 // 默认值
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	if limit > maxMovieTagsLimit {
+		limit = maxMovieTagsLimit
+	}
+
+	tags, err := h.tagService.ListTrendingTags(time.Duration(days)*24*time.Hour, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("Error listing trending tags: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tags, "days": days})
+}
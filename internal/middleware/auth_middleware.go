@@ -1,61 +1,270 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"movie-rating-api/internal/config"
+	"movie-rating-api/internal/models"
 )
 
+// gin上下文中保存认证信息的键
+const (
+	// ContextKeySubject 已认证主体（JWT的sub声明）
+	ContextKeySubject = "authSubject"
+	// ContextKeyClaims 已认证JWT的全部声明（*Claims）
+	ContextKeyClaims = "authClaims"
+)
+
+// APIKeyAuthenticator 校验明文API密钥；未知密钥返回nil，已吊销或已过期返回错误
+type APIKeyAuthenticator interface {
+	Authenticate(rawKey string) (*models.APIKey, error)
+}
+
+// Authorizer 根据主体和凭证携带的角色计算有效角色和权限
+type Authorizer interface {
+	Resolve(subject string, baseRoles []string) ([]string, []string, error)
+}
+
 // AuthMiddleware 认证中间件
 type AuthMiddleware struct {
-	config *config.Config
+	config     *config.Config
+	verifier   *JWTVerifier
+	apiKeys    APIKeyAuthenticator
+	authorizer Authorizer
 }
 
-// NewAuthMiddleware 创建认证中间件实例
-func NewAuthMiddleware(config *config.Config) *AuthMiddleware {
-	return &AuthMiddleware{
-		config: config,
+// NewAuthMiddleware 创建认证中间件实例，配置了JWT密钥时启用JWT校验，
+// apiKeys为nil时不接受API密钥
+func NewAuthMiddleware(config *config.Config, apiKeys APIKeyAuthenticator, authorizer Authorizer) (*AuthMiddleware, error) {
+	verifier, err := NewJWTVerifier(JWTVerifierConfig{
+		HMACSecret:    config.JWTSecret,
+		PublicKeyFile: config.JWTPublicKeyFile,
+		JWKSFile:      config.JWTJWKSFile,
+		Issuer:        config.JWTIssuer,
+		Audience:      config.JWTAudience,
+		ClockSkew:     config.JWTClockSkew,
+		RequireExpiry: config.JWTRequireExpiry,
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return &AuthMiddleware{
+		config:     config,
+		verifier:   verifier,
+		apiKeys:    apiKeys,
+		authorizer: authorizer,
+	}, nil
 }
 
 // RequireAuth 要求认证的中间件
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 获取Authorization头
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+		if !m.requireIdentity(c) {
 			return
 		}
 
-		// 检查Bearer前缀
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
-			return
-		}
+		// 认证成功，继续处理请求
+		c.Next()
+	}
+}
 
-		// 验证token
-		token := parts[1]
-		if token != m.config.AuthToken {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+// RequirePermission 要求调用方具有指定权限的中间件，需在RequireAuth之后使用
+func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !checkPermission(c, permission) {
 			return
 		}
 
-		// 认证成功，继续处理请求
 		c.Next()
 	}
 }
 
-// OptionalAuth 可选认证的中间件（用于健康检查等无需认证的端点）
+// OptionalAuth 可选认证的中间件：没有Authorization头时匿名放行，
+// 携带凭证时按RequireAuth校验并写入身份，便于公开端点返回个性化内容
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 这里可以添加日志记录等，但不阻止请求继续
+		if !m.optionalIdentity(c) {
+			return
+		}
+
 		c.Next()
 	}
 }
+
+// requireIdentity 校验Authorization头并将身份写入上下文，失败时返回401并中止请求
+func (m *AuthMiddleware) requireIdentity(c *gin.Context) bool {
+	// 获取Authorization头
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		c.Abort()
+		return false
+	}
+
+	// 检查Bearer前缀
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		c.Abort()
+		return false
+	}
+
+	// 验证token
+	if errMsg := m.authenticate(c, parts[1]); errMsg != "" {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errMsg})
+		c.Abort()
+		return false
+	}
+
+	// 解析有效角色和权限
+	principal := GetPrincipal(c)
+	if m.authorizer != nil {
+		roles, permissions, err := m.authorizer.Resolve(principal.Subject, principal.Roles)
+		if err != nil {
+			fmt.Printf("Error resolving roles: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve permissions"})
+			c.Abort()
+			return false
+		}
+		principal.Roles = roles
+		principal.Permissions = permissions
+	}
+	principal.Admin = containsString(principal.Roles, models.UserRoleAdmin)
+
+	return true
+}
+
+// optionalIdentity 没有Authorization头时直接返回true，否则按requireIdentity校验；
+// 无效凭证不会被静默忽略，以免调用方误以为自己已登录
+func (m *AuthMiddleware) optionalIdentity(c *gin.Context) bool {
+	if c.GetHeader("Authorization") == "" {
+		return true
+	}
+	return m.requireIdentity(c)
+}
+
+// checkPermission 判定调用方是否具有指定权限，拒绝时记录审计日志并返回403
+func checkPermission(c *gin.Context, permission string) bool {
+	principal := GetPrincipal(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication is required"})
+		c.Abort()
+		return false
+	}
+
+	decision := principal.Decide(permission)
+	if !decision.Allowed {
+		DenyAccess(c, principal, decision)
+		return false
+	}
+	return true
+}
+
+// DenyAccess 记录授权拒绝的审计日志并返回403，响应中包含所需权限、调用方角色和原因，
+// 便于调用方和运维排查
+func DenyAccess(c *gin.Context, principal *Principal, decision models.AuthzDecision) {
+	LogDenial(c, principal, decision)
+
+	if scope, ok := models.PermissionScopes[decision.Permission]; ok && !principal.HasScope(scope) {
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":      "Permission denied",
+		"permission": decision.Permission,
+		"roles":      decision.Roles,
+		"reason":     decision.Reason,
+	})
+	c.Abort()
+}
+
+// LogDenial 记录授权拒绝的审计日志
+func LogDenial(c *gin.Context, principal *Principal, decision models.AuthzDecision) {
+	fmt.Printf("Access denied: method=%s path=%s subject=%q auth=%s key=%q roles=%v permission=%s reason=%q\n",
+		c.Request.Method, c.Request.URL.Path, principal.Subject, principal.Method, principal.KeyID,
+		decision.Roles, decision.Permission, decision.Reason)
+}
+
+// authenticate 校验token：兼容静态AUTH_TOKEN，带API密钥前缀的按API密钥校验，
+// 其余token按JWT校验，并将身份写入上下文。
+// 返回空字符串表示认证成功，否则返回错误描述
+func (m *AuthMiddleware) authenticate(c *gin.Context, token string) string {
+	// 静态AUTH_TOKEN是运维共享凭证，没有具体身份，按管理员处理
+	if m.config.AuthToken != "" && token == m.config.AuthToken {
+		c.Set(ContextKeyPrincipal, &Principal{Method: AuthMethodStaticToken, Roles: []string{models.UserRoleAdmin}})
+		return ""
+	}
+
+	if strings.HasPrefix(token, models.APIKeyPrefix) {
+		return m.authenticateAPIKey(c, token)
+	}
+
+	if m.verifier == nil {
+		return "Invalid token"
+	}
+
+	claims, err := m.verifier.Verify(token)
+	if err != nil {
+		switch err {
+		case ErrTokenExpired:
+			return "Token has expired"
+		case ErrTokenMissingExpiry:
+			return "Token has no expiry"
+		case ErrTokenNotYetValid:
+			return "Token is not valid yet"
+		default:
+			return "Invalid token"
+		}
+	}
+
+	c.Set(ContextKeySubject, claims.Subject)
+	c.Set(ContextKeyClaims, claims)
+	c.Set(ContextKeyPrincipal, principalFromClaims(claims))
+	return ""
+}
+
+// authenticateAPIKey 校验API密钥，密钥绑定了subject时同时写入主体
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, token string) string {
+	if m.apiKeys == nil {
+		return "Invalid token"
+	}
+
+	key, err := m.apiKeys.Authenticate(token)
+	if err != nil {
+		if strings.Contains(err.Error(), "revoked") || strings.Contains(err.Error(), "expired") {
+			return "API key " + strings.TrimPrefix(err.Error(), "api key ")
+		}
+		fmt.Printf("Error authenticating api key: %v\n", err)
+		return "Invalid token"
+	}
+	if key == nil {
+		return "Invalid token"
+	}
+
+	principal := principalFromAPIKey(key)
+	if principal.Subject != "" {
+		c.Set(ContextKeySubject, principal.Subject)
+	}
+	c.Set(ContextKeyPrincipal, principal)
+	return ""
+}
+
+// GetSubject 获取已认证的主体（JWT的sub声明或API密钥绑定的subject），没有主体时返回false
+func GetSubject(c *gin.Context) (string, bool) {
+	subject := c.GetString(ContextKeySubject)
+	return subject, subject != ""
+}
+
+// GetClaims 获取已认证JWT的声明，未通过JWT认证时返回nil
+func GetClaims(c *gin.Context) *Claims {
+	if value, ok := c.Get(ContextKeyClaims); ok {
+		if claims, ok := value.(*Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// CustomMethodRewriter 将AIP风格的自定义方法路径（如 /movies:batchImport）
+// 改写为gin可注册的内部路径。gin会把路径中的':'解析为参数，无法直接注册这类路由，
+// 因此在路由匹配之前完成改写；只改写显式登记的路径，避免误伤含冒号的电影标题
+func CustomMethodRewriter(next http.Handler, rewrites map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target, ok := rewrites[r.URL.Path]; ok {
+			r.URL.Path = target
+			r.URL.RawPath = ""
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,374 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// JWT校验错误
+var (
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenUnsupportedAlg   = errors.New("token signing algorithm is not supported")
+	ErrTokenUnknownKey       = errors.New("token signing key is unknown")
+	ErrTokenInvalidSignature = errors.New("token signature is invalid")
+	ErrTokenExpired          = errors.New("token has expired")
+	ErrTokenMissingExpiry    = errors.New("token expiry is missing")
+	ErrTokenNotYetValid      = errors.New("token is not valid yet")
+	ErrTokenInvalidIssuer    = errors.New("token issuer is invalid")
+	ErrTokenInvalidAudience  = errors.New("token audience is invalid")
+	ErrTokenMissingSubject   = errors.New("token subject is missing")
+)
+
+// Claims JWT中的标准声明，Raw保留全部声明供后续授权使用
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt *time.Time
+	NotBefore *time.Time
+	IssuedAt  *time.Time
+	Raw       map[string]interface{}
+}
+
+// JWTVerifierConfig JWT校验配置
+type JWTVerifierConfig struct {
+	HMACSecret    string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+	ClockSkew     time.Duration
+	// RequireExpiry 是否拒绝没有exp声明的token
+	RequireExpiry bool
+}
+
+// JWTVerifier 校验HS256/RS256签名的JWT，并检查issuer、audience和有效期
+type JWTVerifier struct {
+	hmacKeys      map[string][]byte
+	rsaKeys       map[string]*rsa.PublicKey
+	issuer        string
+	audience      string
+	clockSkew     time.Duration
+	requireExpiry bool
+	now           func() time.Time
+}
+
+// NewJWTVerifier 根据配置加载密钥；没有配置任何密钥时返回nil
+func NewJWTVerifier(cfg JWTVerifierConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmacKeys:      make(map[string][]byte),
+		rsaKeys:       make(map[string]*rsa.PublicKey),
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		clockSkew:     cfg.ClockSkew,
+		requireExpiry: cfg.RequireExpiry,
+		now:           time.Now,
+	}
+
+	if cfg.HMACSecret != "" {
+		v.hmacKeys[""] = []byte(cfg.HMACSecret)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		key, err := loadRSAPublicKeyFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys[""] = key
+	}
+
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKSFile(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// Verify 校验token并返回其中的声明
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	signingInput := parts[0] + "." + parts[1]
+
+	// 算法只从对应类型的密钥集合中取密钥，防止用RSA公钥作为HMAC密钥的算法混淆攻击
+	switch header.Alg {
+	case "HS256":
+		key, ok := lookupKey(v.hmacKeys, header.Kid)
+		if !ok {
+			return nil, ErrTokenUnknownKey
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, ErrTokenInvalidSignature
+		}
+	case "RS256":
+		key, ok := lookupKey(v.rsaKeys, header.Kid)
+		if !ok {
+			return nil, ErrTokenUnknownKey
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, ErrTokenInvalidSignature
+		}
+	default:
+		return nil, ErrTokenUnsupportedAlg
+	}
+
+	var raw map[string]interface{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	claims, err := parseClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validateClaims 检查有效期（允许时钟偏差）、issuer、audience和subject；
+// 要求exp时没有exp声明的token视为无效，避免永不过期的凭证
+func (v *JWTVerifier) validateClaims(claims *Claims) error {
+	now := v.now()
+
+	if claims.ExpiresAt == nil && v.requireExpiry {
+		return ErrTokenMissingExpiry
+	}
+	if claims.ExpiresAt != nil && now.After(claims.ExpiresAt.Add(v.clockSkew)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != nil && now.Add(v.clockSkew).Before(*claims.NotBefore) {
+		return ErrTokenNotYetValid
+	}
+	if claims.IssuedAt != nil && now.Add(v.clockSkew).Before(*claims.IssuedAt) {
+		return ErrTokenNotYetValid
+	}
+
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrTokenInvalidIssuer
+	}
+
+	if v.audience != "" {
+		matched := false
+		for _, aud := range claims.Audience {
+			if aud == v.audience {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrTokenInvalidAudience
+		}
+	}
+
+	if claims.Subject == "" {
+		return ErrTokenMissingSubject
+	}
+	return nil
+}
+
+// parseClaims 从原始声明中提取标准声明
+func parseClaims(raw map[string]interface{}) (*Claims, error) {
+	claims := &Claims{Raw: raw}
+
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Issuer, _ = raw["iss"].(string)
+
+	switch aud := raw["aud"].(type) {
+	case string:
+		claims.Audience = []string{aud}
+	case []interface{}:
+		for _, item := range aud {
+			if s, ok := item.(string); ok {
+				claims.Audience = append(claims.Audience, s)
+			}
+		}
+	case nil:
+	default:
+		return nil, ErrTokenMalformed
+	}
+
+	var err error
+	if claims.ExpiresAt, err = numericDate(raw, "exp"); err != nil {
+		return nil, err
+	}
+	if claims.NotBefore, err = numericDate(raw, "nbf"); err != nil {
+		return nil, err
+	}
+	if claims.IssuedAt, err = numericDate(raw, "iat"); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// numericDate 解析NumericDate类型的声明（Unix秒数）
+func numericDate(raw map[string]interface{}, name string) (*time.Time, error) {
+	value, ok := raw[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	seconds, ok := value.(float64)
+	if !ok {
+		return nil, ErrTokenMalformed
+	}
+
+	t := time.Unix(0, int64(seconds*float64(time.Second)))
+	return &t, nil
+}
+
+// decodeSegment 解码base64url编码的JSON片段
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// lookupKey 按kid查找密钥；token未带kid且只有一个密钥时使用该密钥
+func lookupKey[K any](keys map[string]K, kid string) (K, bool) {
+	if key, ok := keys[kid]; ok {
+		return key, true
+	}
+
+	var zero K
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	return zero, false
+}
+
+// loadRSAPublicKeyFile 从PEM文件加载RSA公钥，支持PKIX公钥、PKCS#1公钥和X.509证书
+func loadRSAPublicKeyFile(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode JWT public key file: no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("JWT public key is not an RSA key")
+		}
+		return rsaKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT certificate: %w", err)
+		}
+		rsaKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("JWT certificate does not contain an RSA key")
+		}
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q in JWT public key file", block.Type)
+	}
+}
+
+// loadJWKSFile 从本地JWKS文件加载密钥，支持RSA（RS256）和oct（HS256）密钥
+func (v *JWTVerifier) loadJWKSFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	for _, jwk := range jwks.Keys {
+		// 只加载用于签名的密钥
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return fmt.Errorf("invalid modulus for JWKS key %q: %w", jwk.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil {
+				return fmt.Errorf("invalid exponent for JWKS key %q: %w", jwk.Kid, err)
+			}
+			v.rsaKeys[jwk.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			k, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil {
+				return fmt.Errorf("invalid secret for JWKS key %q: %w", jwk.Kid, err)
+			}
+			v.hmacKeys[jwk.Kid] = k
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"strings"
+
+	"movie-rating-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 认证方式
+const (
+	AuthMethodStaticToken = "static-token"
+	AuthMethodJWT         = "jwt"
+	AuthMethodAPIKey      = "api-key"
+)
+
+// ContextKeyPrincipal 已认证主体（*Principal）在gin上下文中的键
+const ContextKeyPrincipal = "authPrincipal"
+
+// Principal 已认证的调用方
+type Principal struct {
+	// Subject 调用方身份（JWT的sub声明），静态token没有身份
+	Subject string
+	// Admin 是否具有admin角色
+	Admin bool
+	// Method 认证方式
+	Method string
+	// Scopes 凭证被授予的权限范围，nil表示凭证不受范围限制
+	Scopes []string
+	// KeyID 通过API密钥认证时的密钥ID
+	KeyID string
+	// Roles 有效角色：凭证携带的角色与数据库中授予的角色的并集
+	Roles []string
+	// Permissions 有效角色授予的权限
+	Permissions []string
+}
+
+// HasScope 判断凭证是否具有指定权限范围，admin范围和不受范围限制的凭证具有全部范围。
+// 范围只看凭证本身，数据库中授予主体的角色不会放宽API密钥的范围
+func (p *Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	return containsString(p.Scopes, scope) || containsString(p.Scopes, models.ScopeAdmin)
+}
+
+// Decide 判定调用方是否具有指定权限：角色必须授予该权限，且凭证范围必须覆盖该权限
+func (p *Principal) Decide(permission string) models.AuthzDecision {
+	decision := models.AuthzDecision{Permission: permission, Subject: p.Subject, Roles: p.Roles}
+	if decision.Roles == nil {
+		decision.Roles = []string{}
+	}
+
+	if !containsString(p.Permissions, permission) {
+		decision.Reason = "none of the caller's roles grants " + permission
+		return decision
+	}
+	if scope, ok := models.PermissionScopes[permission]; ok && !p.HasScope(scope) {
+		decision.Reason = "credential is not granted the " + scope + " scope"
+		return decision
+	}
+
+	decision.Allowed = true
+	return decision
+}
+
+// baseRolesFromClaims 根据JWT声明确定凭证携带的角色：roles数组中的角色，
+// 以及admin布尔声明或scope中的admin视为admin角色
+func baseRolesFromClaims(claims *Claims) []string {
+	roles := []string{}
+
+	if admin, ok := claims.Raw["admin"].(bool); ok && admin {
+		roles = append(roles, models.UserRoleAdmin)
+	}
+	if claimed, ok := claims.Raw["roles"].([]interface{}); ok {
+		for _, role := range claimed {
+			if name, ok := role.(string); ok {
+				roles = append(roles, name)
+			}
+		}
+	}
+	if scope, ok := claims.Raw["scope"].(string); ok {
+		for _, s := range strings.Fields(scope) {
+			if s == models.ScopeAdmin {
+				roles = append(roles, models.UserRoleAdmin)
+			}
+		}
+	}
+
+	return roles
+}
+
+// principalFromClaims 根据JWT声明构建调用方。scope声明中出现本服务的
+// 权限范围时，token只具有其中列出的范围（openid等无关scope不做限制）
+func principalFromClaims(claims *Claims) *Principal {
+	principal := &Principal{Subject: claims.Subject, Method: AuthMethodJWT, Roles: baseRolesFromClaims(claims)}
+
+	if scope, ok := claims.Raw["scope"].(string); ok {
+		for _, s := range strings.Fields(scope) {
+			if isServiceScope(s) {
+				principal.Scopes = append(principal.Scopes, s)
+			}
+		}
+	}
+
+	return principal
+}
+
+// apiKeyScopeRoles API密钥的权限范围对应的角色
+var apiKeyScopeRoles = map[string]string{
+	models.ScopeAdmin:        models.UserRoleAdmin,
+	models.ScopeMoviesWrite:  models.UserRoleEditor,
+	models.ScopeRatingsWrite: models.UserRoleRater,
+}
+
+// principalFromAPIKey 根据API密钥构建调用方，密钥的subject作为评分者身份，
+// 角色由密钥的权限范围决定
+func principalFromAPIKey(key *models.APIKey) *Principal {
+	principal := &Principal{Method: AuthMethodAPIKey, Scopes: key.Scopes, KeyID: key.ID, Roles: []string{}}
+	if principal.Scopes == nil {
+		principal.Scopes = []string{}
+	}
+	if key.Subject != nil {
+		principal.Subject = *key.Subject
+	}
+	for _, scope := range key.Scopes {
+		if role, ok := apiKeyScopeRoles[scope]; ok {
+			principal.Roles = append(principal.Roles, role)
+		}
+	}
+	return principal
+}
+
+// isServiceScope 判断是否为本服务定义的权限范围
+func isServiceScope(scope string) bool {
+	return containsString(models.APIKeyScopes, scope)
+}
+
+// containsString 判断切片是否包含指定字符串
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPrincipal 获取已认证的调用方，未认证时返回nil
+func GetPrincipal(c *gin.Context) *Principal {
+	if value, ok := c.Get(ContextKeyPrincipal); ok {
+		if principal, ok := value.(*Principal); ok {
+			return principal
+		}
+	}
+	return nil
+}
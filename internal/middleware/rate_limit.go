@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"movie-rating-api/internal/models"
+)
+
+// RateLimitStore 令牌桶存储
+type RateLimitStore interface {
+	Take(key string, limit models.RateLimit) (*models.RateLimitResult, error)
+	Peek(key string, limit models.RateLimit) (*models.RateLimitResult, error)
+}
+
+// memoryBucket 内存令牌桶
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	period    time.Duration
+}
+
+// MemoryRateLimitStore 进程内令牌桶存储，适用于单副本部署
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore 创建内存令牌桶存储
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*memoryBucket), lastSweep: time.Now()}
+}
+
+// Take 从令牌桶中取一个令牌
+func (s *MemoryRateLimitStore) Take(key string, limit models.RateLimit) (*models.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Requests), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := limit.Take(bucket.tokens, now.Sub(bucket.updatedAt))
+	bucket.tokens = tokens
+	bucket.updatedAt = now
+	bucket.period = limit.Period
+
+	return &result, nil
+}
+
+// Peek 查看令牌桶中是否还有令牌，不取令牌
+func (s *MemoryRateLimitStore) Peek(key string, limit models.RateLimit) (*models.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		return &models.RateLimitResult{Allowed: true, Limit: limit.Requests, Remaining: limit.Requests}, nil
+	}
+
+	_, result := limit.Take(bucket.tokens, time.Since(bucket.updatedAt))
+	return &result, nil
+}
+
+// sweep 每分钟清理一次已补满的空闲桶，避免按IP计数时内存无限增长
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updatedAt) > bucket.period {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// RateLimiter 按调用方和路由限流
+type RateLimiter struct {
+	store            RateLimitStore
+	defaultLimit     *models.RateLimit
+	routes           map[string]*models.RateLimit
+	authFailureLimit *models.RateLimit
+}
+
+// NewRateLimiter 创建限流器。defaultSpec为默认限额（如600/m），routesSpec为逗号分隔的
+// 路由限额（如"POST /movies/:title/ratings=30/m"），authFailureSpec为每个客户端IP
+// 认证失败的限额，限额为off时不限流
+func NewRateLimiter(store RateLimitStore, defaultSpec, routesSpec, authFailureSpec string) (*RateLimiter, error) {
+	defaultLimit, err := ParseRateLimit(defaultSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default rate limit: %w", err)
+	}
+	authFailureLimit, err := ParseRateLimit(authFailureSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth failure rate limit: %w", err)
+	}
+
+	limiter := &RateLimiter{store: store, defaultLimit: defaultLimit, routes: make(map[string]*models.RateLimit), authFailureLimit: authFailureLimit}
+	for _, entry := range strings.Split(routesSpec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, spec, ok := strings.Cut(entry, "=")
+		fields := strings.Fields(route)
+		if !ok || len(fields) != 2 {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected \"METHOD /path=limit\"", entry)
+		}
+		limit, err := ParseRateLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", route, err)
+		}
+		limiter.routes[routeKey(strings.ToUpper(fields[0]), fields[1])] = limit
+	}
+
+	return limiter, nil
+}
+
+// ParseRateLimit 解析"<次数>/<s|m|h>"或"<次数>/<时长>"格式的限额，off或空字符串表示不限流
+func ParseRateLimit(spec string) (*models.RateLimit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "off" {
+		return nil, nil
+	}
+
+	count, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, fmt.Errorf("%q must be <requests>/<period>", spec)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests <= 0 {
+		return nil, fmt.Errorf("%q must have a positive request count", spec)
+	}
+
+	var period time.Duration
+	switch unit = strings.TrimSpace(unit); unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		period, err = time.ParseDuration(unit)
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("%q has an invalid period", spec)
+		}
+	}
+
+	return &models.RateLimit{Requests: requests, Period: period}, nil
+}
+
+// Limit 限流中间件，需在认证之后使用以便按API密钥或评分者计数；
+// 匿名请求按客户端IP计数。有单独限额的路由使用独立的桶
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			c.Next()
+			return
+		}
+
+		bucket := "default"
+		limit := l.defaultLimit
+		if routeLimit, ok := l.routes[routeKey(c.Request.Method, path)]; ok {
+			bucket = routeKey(c.Request.Method, path)
+			limit = routeLimit
+		}
+		if limit == nil {
+			c.Next()
+			return
+		}
+
+		result, err := l.store.Take(rateLimitIdentity(c)+"|"+bucket, *limit)
+		if err != nil {
+			// 存储不可用时放行，限流不应成为单点故障
+			fmt.Printf("Error checking rate limit: %v\n", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Period.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded, retry later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// LimitFailedAuth 按客户端IP限制认证失败的次数，需在认证之前使用。只计入带了
+// Authorization头却认证失败的请求；失败次数用尽的IP在令牌恢复前直接返回429，不再尝试认证
+func (l *RateLimiter) LimitFailedAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.authFailureLimit == nil || c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + c.ClientIP() + "|auth-failures"
+		result, err := l.store.Peek(key, *l.authFailureLimit)
+		if err != nil {
+			// 存储不可用时放行，限流不应成为单点故障
+			fmt.Printf("Error checking auth failure rate limit: %v\n", err)
+		} else if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed authentication attempts, retry later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized {
+			if _, err := l.store.Take(key, *l.authFailureLimit); err != nil {
+				fmt.Printf("Error recording failed authentication: %v\n", err)
+			}
+		}
+	}
+}
+
+// rateLimitIdentity 限流计数的调用方标识：优先API密钥，其次评分者身份，最后客户端IP
+func rateLimitIdentity(c *gin.Context) string {
+	if principal := GetPrincipal(c); principal != nil {
+		if principal.KeyID != "" {
+			return "key:" + principal.KeyID
+		}
+		if principal.Subject != "" {
+			return "rater:" + principal.Subject
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds 将时长向上取整为秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS movie_aliases (
+    id SERIAL PRIMARY KEY,
+    movie_id VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    language VARCHAR(35) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(movie_id, language, title),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+);
+
+-- 创建索引以提高别名查找性能
+CREATE INDEX IF NOT EXISTS idx_movie_aliases_movie_id ON movie_aliases(movie_id);
+CREATE INDEX IF NOT EXISTS idx_movie_aliases_title ON movie_aliases(title);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS people (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    birth_date DATE,
+    biography TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS movie_credits (
+    id SERIAL PRIMARY KEY,
+    movie_id VARCHAR(255) NOT NULL,
+    person_id VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    character_name VARCHAR(255) NOT NULL DEFAULT '',
+    billing_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(movie_id, person_id, role, character_name),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_people_name ON people(name);
+CREATE INDEX IF NOT EXISTS idx_movie_credits_movie_id ON movie_credits(movie_id, billing_order);
+CREATE INDEX IF NOT EXISTS idx_movie_credits_person_id ON movie_credits(person_id);
@@ -0,0 +1,109 @@
+CREATE TABLE IF NOT EXISTS genres (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    -- 归一化键：小写并去掉所有非字母数字字符（"Sci-Fi" -> "scifi"）
+    key VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同义词表，将常见写法映射到标准类型
+CREATE TABLE IF NOT EXISTS genre_synonyms (
+    key VARCHAR(100) PRIMARY KEY,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS movie_genres (
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (movie_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_genres_genre_id ON movie_genres(genre_id);
+
+-- 预置标准类型
+INSERT INTO genres (slug, name, key) VALUES
+    ('action', 'Action', 'action'),
+    ('adventure', 'Adventure', 'adventure'),
+    ('animation', 'Animation', 'animation'),
+    ('comedy', 'Comedy', 'comedy'),
+    ('crime', 'Crime', 'crime'),
+    ('documentary', 'Documentary', 'documentary'),
+    ('drama', 'Drama', 'drama'),
+    ('family', 'Family', 'family'),
+    ('fantasy', 'Fantasy', 'fantasy'),
+    ('history', 'History', 'history'),
+    ('horror', 'Horror', 'horror'),
+    ('music', 'Music', 'music'),
+    ('mystery', 'Mystery', 'mystery'),
+    ('romance', 'Romance', 'romance'),
+    ('science-fiction', 'Science Fiction', 'sciencefiction'),
+    ('thriller', 'Thriller', 'thriller'),
+    ('war', 'War', 'war'),
+    ('western', 'Western', 'western')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO genre_synonyms (key, genre_id)
+SELECT s.key, g.id
+FROM (VALUES
+    ('scifi', 'science-fiction'),
+    ('sf', 'science-fiction'),
+    ('科幻', 'science-fiction'),
+    ('animated', 'animation'),
+    ('anime', 'animation'),
+    ('doc', 'documentary'),
+    ('historical', 'history'),
+    ('musical', 'music'),
+    ('romantic', 'romance'),
+    ('suspense', 'thriller'),
+    ('动作', 'action'),
+    ('喜剧', 'comedy'),
+    ('剧情', 'drama'),
+    ('动画', 'animation'),
+    ('恐怖', 'horror'),
+    ('爱情', 'romance')
+) AS s(key, slug)
+JOIN genres g ON g.slug = s.slug
+ON CONFLICT DO NOTHING;
+
+-- 迁移已有的genre值：按 , / | 拆分并归一化，未知的类型加入分类表
+CREATE TEMP TABLE legacy_movie_genres AS
+SELECT m.id AS movie_id,
+       TRIM(part.value) AS name,
+       REGEXP_REPLACE(LOWER(TRIM(part.value)), '[^[:alnum:]]', '', 'g') AS key,
+       part.position
+FROM movies m,
+     LATERAL REGEXP_SPLIT_TO_TABLE(m.genre, '[,/|]') WITH ORDINALITY AS part(value, position)
+WHERE TRIM(part.value) <> '';
+
+INSERT INTO genres (slug, name, key)
+SELECT DISTINCT ON (l.key)
+       TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(l.name), '[^[:alnum:]]+', '-', 'g')),
+       l.name,
+       l.key
+FROM legacy_movie_genres l
+WHERE l.key <> ''
+  AND NOT EXISTS (SELECT 1 FROM genres g WHERE g.key = l.key)
+  AND NOT EXISTS (SELECT 1 FROM genre_synonyms s WHERE s.key = l.key)
+ORDER BY l.key, l.name
+ON CONFLICT DO NOTHING;
+
+INSERT INTO movie_genres (movie_id, genre_id, position)
+SELECT l.movie_id, COALESCE(g.id, s.genre_id), MIN(l.position) - 1
+FROM legacy_movie_genres l
+LEFT JOIN genres g ON g.key = l.key
+LEFT JOIN genre_synonyms s ON s.key = l.key
+WHERE COALESCE(g.id, s.genre_id) IS NOT NULL
+GROUP BY l.movie_id, COALESCE(g.id, s.genre_id)
+ON CONFLICT DO NOTHING;
+
+-- 将movies.genre统一为主类型的标准名称
+UPDATE movies m
+SET genre = g.name
+FROM movie_genres mg
+JOIN genres g ON g.id = mg.genre_id
+WHERE mg.movie_id = m.id
+  AND mg.position = (SELECT MIN(position) FROM movie_genres WHERE movie_id = m.id);
+
+DROP TABLE legacy_movie_genres;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS collections (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS collection_movies (
+    collection_id VARCHAR(255) NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, movie_id)
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_collections_name ON collections(name);
+CREATE INDEX IF NOT EXISTS idx_collection_movies_movie_id ON collection_movies(movie_id);
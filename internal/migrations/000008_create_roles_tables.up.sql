@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+-- 主体为JWT的sub或API密钥绑定的subject
+CREATE TABLE IF NOT EXISTS role_assignments (
+    subject VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject, role)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('rater', 'Can submit and manage their own ratings'),
+    ('editor', 'Can create and edit movies, people, genres and collections'),
+    ('admin', 'Can delete catalog entries, manage API keys and roles, and act on behalf of raters')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('rater', 'ratings:write'),
+    ('editor', 'ratings:write'),
+    ('editor', 'movies:write'),
+    ('editor', 'data:export'),
+    ('admin', 'ratings:write'),
+    ('admin', 'ratings:impersonate'),
+    ('admin', 'movies:write'),
+    ('admin', 'movies:delete'),
+    ('admin', 'keys:manage'),
+    ('admin', 'roles:manage'),
+    ('admin', 'data:export')
+ON CONFLICT DO NOTHING;
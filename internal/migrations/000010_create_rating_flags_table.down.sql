@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'ratings:moderate';
+DROP TABLE IF EXISTS rating_flags;
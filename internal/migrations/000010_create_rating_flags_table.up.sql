@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS rating_flags (
+    id BIGSERIAL PRIMARY KEY,
+    movie_title VARCHAR(255) NOT NULL,
+    rater_id VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'cleared')),
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- 同一评分因同一原因只标记一次；评分被删除时标记一并删除
+    UNIQUE (movie_title, rater_id, reason),
+    FOREIGN KEY (movie_title, rater_id) REFERENCES ratings(movie_title, rater_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_flags_status ON rating_flags(status, created_at);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'ratings:moderate')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+-- 评分变更事件，只追加不修改；不引用ratings，评分删除后事件仍然保留
+CREATE TABLE IF NOT EXISTS rating_events (
+    id BIGSERIAL PRIMARY KEY,
+    movie_title VARCHAR(255) NOT NULL,
+    rater_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('created', 'updated', 'deleted')),
+    old_rating FLOAT,
+    new_rating FLOAT,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_events_movie_time ON rating_events(movie_title, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_rating_events_rater ON rating_events(rater_id);
+
+-- 已有评分没有历史，按当前分数补一条创建事件（更早的分数已无从得知）
+INSERT INTO rating_events (movie_title, rater_id, event_type, old_rating, new_rating, occurred_at)
+SELECT movie_title, rater_id, 'created', NULL, rating, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM ratings;
@@ -0,0 +1,19 @@
+-- 每部电影的评分聚合，随评分写入在同一事务中增量维护
+CREATE TABLE IF NOT EXISTS movie_rating_stats (
+    movie_title VARCHAR(255) PRIMARY KEY REFERENCES movies(title) ON DELETE CASCADE,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    rating_sum DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rating_avg DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE WHEN rating_count > 0 THEN rating_sum / rating_count ELSE 0 END
+    ) STORED,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_rating_stats_avg ON movie_rating_stats(rating_avg DESC, rating_count DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_rating_stats_count ON movie_rating_stats(rating_count DESC);
+
+INSERT INTO movie_rating_stats (movie_title, rating_count, rating_sum)
+SELECT movie_title, COUNT(*), SUM(rating)
+FROM ratings
+GROUP BY movie_title
+ON CONFLICT (movie_title) DO NOTHING;
@@ -0,0 +1,11 @@
+-- 基于评分的电影相似度，由后台任务整体重算后替换
+CREATE TABLE IF NOT EXISTS movie_similarities (
+    movie_title VARCHAR(255) NOT NULL REFERENCES movies(title) ON DELETE CASCADE ON UPDATE CASCADE,
+    similar_title VARCHAR(255) NOT NULL REFERENCES movies(title) ON DELETE CASCADE ON UPDATE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    common_raters INTEGER NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (movie_title, similar_title)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_similarities_score ON movie_similarities(movie_title, score DESC);
@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission = 'lists:write';
+DROP TABLE IF EXISTS user_list_items;
+DROP TABLE IF EXISTS user_lists;
@@ -0,0 +1,33 @@
+-- 评分者拥有的电影列表：每人一个待看列表（watchlist），以及任意数量的自定义列表
+CREATE TABLE IF NOT EXISTS user_lists (
+    id VARCHAR(255) PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    kind VARCHAR(20) NOT NULL DEFAULT 'custom' CHECK (kind IN ('watchlist', 'custom')),
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('public', 'private')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_lists_watchlist ON user_lists(owner_id) WHERE kind = 'watchlist';
+CREATE INDEX IF NOT EXISTS idx_user_lists_owner ON user_lists(owner_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_lists_public ON user_lists(updated_at DESC) WHERE visibility = 'public';
+
+CREATE TABLE IF NOT EXISTS user_list_items (
+    list_id VARCHAR(255) NOT NULL REFERENCES user_lists(id) ON DELETE CASCADE,
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    note TEXT,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    watched_at TIMESTAMP,
+    PRIMARY KEY (list_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_list_items_movie_id ON user_list_items(movie_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('rater', 'lists:write'),
+    ('editor', 'lists:write'),
+    ('admin', 'lists:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_reactions;
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,32 @@
+-- 评论：评分时附带的文字，每位评分者对每部电影一条，随评分删除
+CREATE TABLE IF NOT EXISTS reviews (
+    id BIGSERIAL PRIMARY KEY,
+    movie_title VARCHAR(255) NOT NULL,
+    rater_id VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (movie_title, rater_id),
+    FOREIGN KEY (movie_title, rater_id) REFERENCES ratings(movie_title, rater_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_movie ON reviews(movie_title, created_at DESC);
+
+-- 有用/无用投票，主键保证每位评分者对每条评论只有一票
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id BIGINT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    voter_id VARCHAR(255) NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (review_id, voter_id)
+);
+
+-- 表情回应，每位评分者对每条评论的每种回应只计一次
+CREATE TABLE IF NOT EXISTS review_reactions (
+    review_id BIGINT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    rater_id VARCHAR(255) NOT NULL,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (review_id, rater_id, reaction)
+);
@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'reviews:moderate';
+DELETE FROM roles WHERE name = 'moderator';
+DROP TABLE IF EXISTS review_reports;
+DROP TABLE IF EXISTS review_moderation_events;
+DROP INDEX IF EXISTS idx_reviews_status;
+ALTER TABLE reviews DROP COLUMN IF EXISTS status;
@@ -0,0 +1,44 @@
+-- 评论审核状态：新评论和修改过的评论为pending，只有approved的评论公开显示。
+-- 已有评论在引入审核前就已公开，保持可见
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'approved', 'rejected', 'hidden'));
+UPDATE reviews SET status = 'approved';
+
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status, created_at);
+
+-- 每次审核决定（自动审核、版主和举报触发的重新审核）都记录审核人和原因
+CREATE TABLE IF NOT EXISTS review_moderation_events (
+    id BIGSERIAL PRIMARY KEY,
+    review_id BIGINT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    moderator VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_moderation_events_review ON review_moderation_events(review_id, id);
+
+-- 用户举报，每位评分者对每条评论只能举报一次；审核决定后举报标记为已处理
+CREATE TABLE IF NOT EXISTS review_reports (
+    id BIGSERIAL PRIMARY KEY,
+    review_id BIGINT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    reporter_id VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    UNIQUE (review_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_reports_open ON review_reports(review_id) WHERE status = 'open';
+
+INSERT INTO roles (name, description) VALUES
+    ('moderator', 'Can review the moderation queue and approve, reject or hide reviews')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'reviews:moderate'),
+    ('admin', 'reviews:moderate')
+ON CONFLICT DO NOTHING;
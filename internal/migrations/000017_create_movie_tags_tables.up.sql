@@ -0,0 +1,21 @@
+-- 评分者为电影添加的自由标签（"mind-bending"、"slow burn"）
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    -- 首次使用时的规范写法：小写、首尾去空白、连续空白合并为一个空格
+    name VARCHAR(50) NOT NULL,
+    -- 归一化键：去掉所有非字母数字字符，"Mind-Bending"与"mind bending"视为同一标签
+    key VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 每位评分者对每部电影的每个标签只计一次
+CREATE TABLE IF NOT EXISTS movie_tags (
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    rater_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (movie_id, tag_id, rater_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_tags_tag_id ON movie_tags(tag_id, movie_id);
+CREATE INDEX IF NOT EXISTS idx_movie_tags_created_at ON movie_tags(created_at);
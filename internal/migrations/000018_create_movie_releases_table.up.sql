@@ -0,0 +1,16 @@
+-- 按地区、发行渠道记录的上映日期；movies.release_date保留为电影的首映日期
+CREATE TABLE IF NOT EXISTS movie_releases (
+    id BIGSERIAL PRIMARY KEY,
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    -- ISO 3166-1 alpha-2地区代码
+    region CHAR(2) NOT NULL CHECK (region ~ '^[A-Z]{2}$'),
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('theatrical', 'streaming', 'physical')),
+    release_date DATE NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (movie_id, region, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_releases_date ON movie_releases(release_date);
+CREATE INDEX IF NOT EXISTS idx_movie_releases_region_date ON movie_releases(region, release_date);
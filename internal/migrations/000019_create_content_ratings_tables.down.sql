@@ -0,0 +1,16 @@
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS mpa_rating VARCHAR(10);
+
+UPDATE movies m SET mpa_rating = cr.rating
+FROM movie_content_ratings cr
+WHERE cr.movie_id = m.id AND cr.system = 'MPA';
+
+-- 没有MPA分级的电影恢复迁移时无法识别的旧值
+UPDATE movies m SET mpa_rating = l.mpa_rating
+FROM legacy_mpa_ratings l
+WHERE l.movie_id = m.id AND m.mpa_rating IS NULL;
+
+DROP TABLE IF EXISTS legacy_mpa_ratings;
+
+DROP TABLE IF EXISTS movie_content_ratings;
+DROP TABLE IF EXISTS content_rating_levels;
+DROP TABLE IF EXISTS content_rating_systems;
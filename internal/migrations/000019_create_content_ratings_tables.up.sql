@@ -0,0 +1,103 @@
+-- 分级制度及其所属地区
+CREATE TABLE IF NOT EXISTS content_rating_systems (
+    code VARCHAR(20) PRIMARY KEY,
+    region CHAR(2) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+-- 各分级制度的级别。min_age为适合观看的最低年龄，"家长指导"类的建议级别按保守年龄映射，
+-- 用于跨制度的"适合N岁"过滤
+CREATE TABLE IF NOT EXISTS content_rating_levels (
+    system VARCHAR(20) NOT NULL REFERENCES content_rating_systems(code) ON DELETE CASCADE,
+    code VARCHAR(20) NOT NULL,
+    min_age INTEGER NOT NULL CHECK (min_age >= 0),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (system, code)
+);
+
+-- 电影在各分级制度下的级别，每个制度一个
+CREATE TABLE IF NOT EXISTS movie_content_ratings (
+    movie_id VARCHAR(255) NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    system VARCHAR(20) NOT NULL,
+    rating VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (movie_id, system),
+    FOREIGN KEY (system, rating) REFERENCES content_rating_levels(system, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_content_ratings_level ON movie_content_ratings(system, rating);
+
+INSERT INTO content_rating_systems (code, region, name, position) VALUES
+    ('MPA', 'US', 'Motion Picture Association', 1),
+    ('BBFC', 'GB', 'British Board of Film Classification', 2),
+    ('FSK', 'DE', 'Freiwillige Selbstkontrolle der Filmwirtschaft', 3),
+    ('CBFC', 'IN', 'Central Board of Film Certification', 4),
+    ('ACB', 'AU', 'Australian Classification Board', 5),
+    ('EIRIN', 'JP', 'Film Classification and Rating Organization', 6)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO content_rating_levels (system, code, min_age, position) VALUES
+    ('MPA', 'G', 0, 1),
+    ('MPA', 'PG', 8, 2),
+    ('MPA', 'PG-13', 13, 3),
+    ('MPA', 'R', 17, 4),
+    ('MPA', 'NC-17', 18, 5),
+    ('BBFC', 'U', 0, 1),
+    ('BBFC', 'PG', 8, 2),
+    ('BBFC', '12A', 12, 3),
+    ('BBFC', '12', 12, 4),
+    ('BBFC', '15', 15, 5),
+    ('BBFC', '18', 18, 6),
+    ('BBFC', 'R18', 18, 7),
+    ('FSK', '0', 0, 1),
+    ('FSK', '6', 6, 2),
+    ('FSK', '12', 12, 3),
+    ('FSK', '16', 16, 4),
+    ('FSK', '18', 18, 5),
+    ('CBFC', 'U', 0, 1),
+    ('CBFC', 'UA 7+', 7, 2),
+    ('CBFC', 'UA', 12, 3),
+    ('CBFC', 'UA 13+', 13, 4),
+    ('CBFC', 'UA 16+', 16, 5),
+    ('CBFC', 'A', 18, 6),
+    ('CBFC', 'S', 18, 7),
+    ('ACB', 'G', 0, 1),
+    ('ACB', 'PG', 8, 2),
+    ('ACB', 'M', 15, 3),
+    ('ACB', 'MA15+', 15, 4),
+    ('ACB', 'R18+', 18, 5),
+    ('ACB', 'X18+', 18, 6),
+    ('EIRIN', 'G', 0, 1),
+    ('EIRIN', 'PG12', 12, 2),
+    ('EIRIN', 'R15+', 15, 3),
+    ('EIRIN', 'R18+', 18, 4)
+ON CONFLICT (system, code) DO NOTHING;
+
+-- 迁移已有的MPA分级：忽略大小写和标点匹配（"pg13" -> "PG-13"）
+INSERT INTO movie_content_ratings (movie_id, system, rating)
+SELECT m.id, l.system, l.code
+FROM movies m
+JOIN content_rating_levels l
+  ON l.system = 'MPA'
+ AND regexp_replace(UPPER(l.code), '[^A-Z0-9]', '', 'g') = regexp_replace(UPPER(m.mpa_rating), '[^A-Z0-9]', '', 'g')
+WHERE m.mpa_rating IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- 无法识别的旧分级值（如 "NR"）原样保留，便于人工补录，回滚时也据此恢复
+CREATE TABLE IF NOT EXISTS legacy_mpa_ratings (
+    movie_id VARCHAR(255) PRIMARY KEY REFERENCES movies(id) ON DELETE CASCADE,
+    mpa_rating VARCHAR(10) NOT NULL
+);
+
+INSERT INTO legacy_mpa_ratings (movie_id, mpa_rating)
+SELECT m.id, m.mpa_rating
+FROM movies m
+WHERE m.mpa_rating IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM movie_content_ratings cr WHERE cr.movie_id = m.id AND cr.system = 'MPA'
+  )
+ON CONFLICT (movie_id) DO NOTHING;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS mpa_rating;
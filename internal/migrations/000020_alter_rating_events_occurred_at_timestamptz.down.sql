@@ -0,0 +1,2 @@
+ALTER TABLE rating_events
+    ALTER COLUMN occurred_at TYPE TIMESTAMP USING occurred_at::timestamp;
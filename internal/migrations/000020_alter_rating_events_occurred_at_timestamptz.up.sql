@@ -0,0 +1,4 @@
+-- 事件时间改为带时区的时间戳，asOf查询不再受数据库或会话时区影响。
+-- 已有的值由CURRENT_TIMESTAMP按会话时区写入，按同一时区解释
+ALTER TABLE rating_events
+    ALTER COLUMN occurred_at TYPE TIMESTAMPTZ USING occurred_at::timestamptz;
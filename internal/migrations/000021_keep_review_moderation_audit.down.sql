@@ -0,0 +1,16 @@
+DELETE FROM review_reports WHERE review_id IS NULL;
+ALTER TABLE review_reports DROP CONSTRAINT IF EXISTS review_reports_review_id_fkey;
+ALTER TABLE review_reports ADD CONSTRAINT review_reports_review_id_fkey
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE;
+ALTER TABLE review_reports ALTER COLUMN review_id SET NOT NULL;
+ALTER TABLE review_reports DROP COLUMN IF EXISTS rater_id;
+ALTER TABLE review_reports DROP COLUMN IF EXISTS movie_title;
+
+DROP INDEX IF EXISTS idx_review_moderation_events_author;
+DELETE FROM review_moderation_events WHERE review_id IS NULL;
+ALTER TABLE review_moderation_events DROP CONSTRAINT IF EXISTS review_moderation_events_review_id_fkey;
+ALTER TABLE review_moderation_events ADD CONSTRAINT review_moderation_events_review_id_fkey
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE;
+ALTER TABLE review_moderation_events ALTER COLUMN review_id SET NOT NULL;
+ALTER TABLE review_moderation_events DROP COLUMN IF EXISTS rater_id;
+ALTER TABLE review_moderation_events DROP COLUMN IF EXISTS movie_title;
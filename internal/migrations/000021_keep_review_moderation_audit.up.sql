@@ -0,0 +1,27 @@
+-- 评分撤回时评论随之删除，审核记录和举报作为审计记录保留：
+-- 记录电影和评分者，评论删除后review_id置空
+ALTER TABLE review_moderation_events ADD COLUMN IF NOT EXISTS movie_title VARCHAR(255);
+ALTER TABLE review_moderation_events ADD COLUMN IF NOT EXISTS rater_id VARCHAR(255);
+UPDATE review_moderation_events e SET movie_title = r.movie_title, rater_id = r.rater_id
+FROM reviews r
+WHERE r.id = e.review_id;
+ALTER TABLE review_moderation_events ALTER COLUMN movie_title SET NOT NULL;
+ALTER TABLE review_moderation_events ALTER COLUMN rater_id SET NOT NULL;
+ALTER TABLE review_moderation_events ALTER COLUMN review_id DROP NOT NULL;
+ALTER TABLE review_moderation_events DROP CONSTRAINT IF EXISTS review_moderation_events_review_id_fkey;
+ALTER TABLE review_moderation_events ADD CONSTRAINT review_moderation_events_review_id_fkey
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_review_moderation_events_author ON review_moderation_events(movie_title, rater_id, id);
+
+ALTER TABLE review_reports ADD COLUMN IF NOT EXISTS movie_title VARCHAR(255);
+ALTER TABLE review_reports ADD COLUMN IF NOT EXISTS rater_id VARCHAR(255);
+UPDATE review_reports rr SET movie_title = r.movie_title, rater_id = r.rater_id
+FROM reviews r
+WHERE r.id = rr.review_id;
+ALTER TABLE review_reports ALTER COLUMN movie_title SET NOT NULL;
+ALTER TABLE review_reports ALTER COLUMN rater_id SET NOT NULL;
+ALTER TABLE review_reports ALTER COLUMN review_id DROP NOT NULL;
+ALTER TABLE review_reports DROP CONSTRAINT IF EXISTS review_reports_review_id_fkey;
+ALTER TABLE review_reports ADD CONSTRAINT review_reports_review_id_fkey
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE SET NULL;
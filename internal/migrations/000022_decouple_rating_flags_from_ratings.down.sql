@@ -0,0 +1,7 @@
+DELETE FROM rating_flags f
+WHERE NOT EXISTS (
+    SELECT 1 FROM ratings r WHERE r.movie_title = f.movie_title AND r.rater_id = f.rater_id
+);
+ALTER TABLE rating_flags DROP CONSTRAINT IF EXISTS rating_flags_movie_title_fkey;
+ALTER TABLE rating_flags ADD CONSTRAINT rating_flags_movie_title_rater_id_fkey
+    FOREIGN KEY (movie_title, rater_id) REFERENCES ratings(movie_title, rater_id) ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,5 @@
+-- 标记按电影和评分者记录，不随评分删除：撤回被确认刷分的评分后重新提交，
+-- 原有的标记仍然适用，评分继续不计入聚合
+ALTER TABLE rating_flags DROP CONSTRAINT IF EXISTS rating_flags_movie_title_rater_id_fkey;
+ALTER TABLE rating_flags ADD CONSTRAINT rating_flags_movie_title_fkey
+    FOREIGN KEY (movie_title) REFERENCES movies(title) ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,15 @@
+package models
+
+// MovieAlias 电影别名/本地化标题模型
+type MovieAlias struct {
+	ID       int64  `json:"id" db:"id"`
+	MovieID  string `json:"movieId" db:"movie_id"`
+	Title    string `json:"title" db:"title"`
+	Language string `json:"language" db:"language"`
+}
+
+// MovieAliasCreate 创建别名请求
+type MovieAliasCreate struct {
+	Title    string `json:"title" binding:"required"`
+	Language string `json:"language" binding:"required"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// API密钥权限范围
+const (
+	ScopeMoviesWrite  = "movies:write"
+	ScopeRatingsWrite = "ratings:write"
+	ScopeDataExport   = "data:export"
+	ScopeAdmin        = "admin"
+)
+
+// APIKeyPrefix 明文API密钥的固定前缀，认证时据此与JWT区分
+const APIKeyPrefix = "mrk_"
+
+// APIKeyScopes 所有合法的权限范围
+var APIKeyScopes = []string{ScopeMoviesWrite, ScopeRatingsWrite, ScopeDataExport, ScopeAdmin}
+
+// APIKey API密钥模型，只保存密钥哈希
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	Subject    *string    `json:"subject,omitempty" db:"subject"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// APIKeyCreate 创建API密钥请求，subject为使用该密钥时的评分者身份
+type APIKeyCreate struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	Subject   *string    `json:"subject,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyCreated 创建API密钥响应，明文密钥只在此时返回一次
+type APIKeyCreated struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// Collection 电影合集/系列模型（如《黑暗骑士》三部曲）
+type Collection struct {
+	ID          string  `json:"id" db:"id"`
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description,omitempty" db:"description"`
+}
+
+// CollectionCreate 创建合集请求
+type CollectionCreate struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description,omitempty"`
+}
+
+// CollectionMemberAdd 向合集添加电影请求，未指定position时追加到末尾
+type CollectionMemberAdd struct {
+	Title    string `json:"title" binding:"required"`
+	Position *int   `json:"position,omitempty"`
+}
+
+// CollectionMember 合集成员及其评分和票房
+type CollectionMember struct {
+	Position int             `json:"position"`
+	Movie    Movie           `json:"movie"`
+	Rating   RatingAggregate `json:"rating"`
+}
+
+// CollectionAggregates 合集层面的聚合数据
+type CollectionAggregates struct {
+	MovieCount int `json:"movieCount"`
+	// 所有成员全球票房之和（USD），只统计有票房数据的成员
+	WorldwideBoxOffice  int64 `json:"worldwideBoxOffice"`
+	MoviesWithBoxOffice int   `json:"moviesWithBoxOffice"`
+	// 各成员平均分的平均值，只统计有评分的成员
+	AverageRating float64 `json:"averageRating"`
+	RatingCount   int     `json:"ratingCount"`
+}
+
+// CollectionDetail 合集详情响应
+type CollectionDetail struct {
+	Collection
+	Members    []CollectionMember   `json:"members"`
+	Aggregates CollectionAggregates `json:"aggregates"`
+}
+
+// CollectionPage 合集分页响应
+type CollectionPage struct {
+	Items      []Collection `json:"items"`
+	NextCursor *string      `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ContentRatingSystemMPA 美国MPA分级，对应兼容字段mpaRating
+const ContentRatingSystemMPA = "MPA"
+
+// ContentRatingSystem 分级制度及其级别
+type ContentRatingSystem struct {
+	Code    string               `json:"code"`
+	Region  string               `json:"region"`
+	Name    string               `json:"name"`
+	Ratings []ContentRatingLevel `json:"ratings"`
+}
+
+// ContentRatingLevel 分级制度中的一个级别，MinAge为适合观看的最低年龄
+type ContentRatingLevel struct {
+	Code   string `json:"code"`
+	MinAge int    `json:"minAge"`
+}
+
+// ContentRating 电影在某分级制度下的级别
+type ContentRating struct {
+	System string `json:"system"`
+	Region string `json:"region"`
+	Rating string `json:"rating"`
+	MinAge int    `json:"minAge"`
+}
+
+// ContentRatingInput 创建电影时提交的分级
+type ContentRatingInput struct {
+	System string `json:"system" binding:"required"`
+	Rating string `json:"rating" binding:"required"`
+}
+
+// ContentRatingSet 设置电影在某分级制度下的级别请求
+type ContentRatingSet struct {
+	Rating string `json:"rating" binding:"required"`
+}
+
+// ContentRatingKey 计算分级的归一化键：大写并去掉所有非字母数字字符，
+// 使 "pg-13"、"PG 13" 与 "PG-13" 得到相同的键
+func ContentRatingKey(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FindLevel 在分级制度中查找级别，忽略大小写、标点以及制度名前缀（"FSK 12" -> "12"）
+func (s *ContentRatingSystem) FindLevel(rating string) *ContentRatingLevel {
+	key := ContentRatingKey(rating)
+	prefixed := strings.TrimPrefix(key, ContentRatingKey(s.Code))
+	for i := range s.Ratings {
+		levelKey := ContentRatingKey(s.Ratings[i].Code)
+		if levelKey == key || (prefixed != "" && levelKey == prefixed) {
+			return &s.Ratings[i]
+		}
+	}
+	return nil
+}
+
+// LevelCodes 返回分级制度的全部级别代码
+func (s *ContentRatingSystem) LevelCodes() []string {
+	codes := make([]string, 0, len(s.Ratings))
+	for _, level := range s.Ratings {
+		codes = append(codes, level.Code)
+	}
+	return codes
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MovieExport 导出的电影记录，包含评分聚合
+type MovieExport struct {
+	Movie
+	Rating RatingAggregate `json:"rating"`
+}
+
+// RatingExport 导出的原始评分记录
+type RatingExport struct {
+	MovieTitle string    `json:"movieTitle"`
+	RaterID    string    `json:"raterId"`
+	Rating     float64   `json:"rating"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
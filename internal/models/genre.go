@@ -0,0 +1,48 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Genre 电影类型模型
+type Genre struct {
+	ID         int64  `json:"id" db:"id"`
+	Slug       string `json:"slug" db:"slug"`
+	Name       string `json:"name" db:"name"`
+	MovieCount int    `json:"movieCount" db:"-"`
+}
+
+// GenreCreate 创建类型请求
+type GenreCreate struct {
+	Name     string   `json:"name" binding:"required"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// GenreKey 计算类型的归一化键：小写并去掉所有非字母数字字符，
+// 使 "Sci-Fi"、"sci fi"、"SciFi" 得到相同的键
+func GenreKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// GenreSlug 生成类型的URL友好标识（"Science Fiction" -> "science-fiction"）
+func GenreSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,49 @@
+package models
+
+// 批量导入模式
+const (
+	// ImportModeAtomic 全部成功或全部回滚
+	ImportModeAtomic = "atomic"
+	// ImportModeBestEffort 尽力导入，失败的行不影响其他行
+	ImportModeBestEffort = "best-effort"
+)
+
+// 批量导入的行状态
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportOptions 批量导入选项
+type ImportOptions struct {
+	Mode string
+	// 是否为每部电影调用票房API，大批量导入时默认关闭
+	FetchBoxOffice bool
+}
+
+// ImportRow 解析后的导入行，ParseError非空表示该行无法解析
+type ImportRow struct {
+	Row        int
+	Movie      MovieCreate
+	ParseError error
+}
+
+// ImportRowResult 单行导入结果
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport 批量导入报告
+type ImportReport struct {
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// 列表类型
+const (
+	ListKindWatchlist = "watchlist"
+	ListKindCustom    = "custom"
+)
+
+// 列表可见性
+const (
+	ListVisibilityPublic  = "public"
+	ListVisibilityPrivate = "private"
+)
+
+// UserList 评分者拥有的电影列表（待看列表或自定义列表）
+type UserList struct {
+	ID          string    `json:"id" db:"id"`
+	OwnerID     string    `json:"ownerId" db:"owner_id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Kind        string    `json:"kind" db:"kind"`
+	Visibility  string    `json:"visibility" db:"visibility"`
+	ItemCount   int       `json:"itemCount" db:"-"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// UserListCreate 创建自定义列表请求，可见性默认为private
+type UserListCreate struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description,omitempty"`
+	Visibility  string  `json:"visibility,omitempty"`
+}
+
+// UserListUpdate 更新列表请求，只修改提供的字段
+type UserListUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Visibility  *string `json:"visibility,omitempty"`
+}
+
+// UserListItemAdd 向列表添加电影请求，未指定position时追加到末尾
+type UserListItemAdd struct {
+	Title    string  `json:"title" binding:"required"`
+	Position *int    `json:"position,omitempty"`
+	Note     *string `json:"note,omitempty"`
+}
+
+// UserListItem 列表中的一部电影
+type UserListItem struct {
+	Position    int       `json:"position" db:"position"`
+	MovieTitle  string    `json:"movieTitle" db:"movie_title"`
+	ReleaseDate string    `json:"releaseDate" db:"release_date"`
+	Note        *string   `json:"note,omitempty" db:"note"`
+	AddedAt     time.Time `json:"addedAt" db:"added_at"`
+	// WatchedAt 待看列表中的电影被评分者评分的时间
+	WatchedAt *time.Time `json:"watchedAt,omitempty" db:"watched_at"`
+}
+
+// UserListPage 列表分页响应
+type UserListPage struct {
+	Items      []UserList `json:"items"`
+	NextCursor *string    `json:"nextCursor,omitempty"`
+}
+
+// UserListItemPage 列表电影分页响应
+type UserListItemPage struct {
+	Items      []UserListItem `json:"items"`
+	NextCursor *string        `json:"nextCursor,omitempty"`
+}
@@ -11,10 +11,21 @@ type Movie struct {
 	Title       string     `json:"title" db:"title" binding:"required"`
 	ReleaseDate string     `json:"releaseDate" db:"release_date" binding:"required"`
 	Genre       string     `json:"genre" db:"genre" binding:"required"`
+	Genres      []string   `json:"genres" db:"-"`
 	Distributor *string    `json:"distributor,omitempty" db:"distributor"`
 	Budget      *int64     `json:"budget,omitempty" db:"budget"`
-	MPARating   *string    `json:"mpaRating,omitempty" db:"mpa_rating"`
 	BoxOffice   *BoxOffice `json:"boxOffice,omitempty" db:"box_office"`
+
+	// ContentRatings 各分级制度下的级别；MPARating为其中MPA的级别，保留以兼容旧客户端
+	ContentRatings []ContentRating `json:"contentRatings" db:"-"`
+	MPARating      *string         `json:"mpaRating,omitempty" db:"-"`
+
+	// Ratings 物化评分聚合，仅在列表中返回
+	Ratings *RatingAggregate `json:"ratings,omitempty" db:"-"`
+
+	// 根据Accept-Language协商得到的本地化标题，不存储在movies表中
+	LocalizedTitle *string `json:"localizedTitle,omitempty" db:"-"`
+	TitleLanguage  *string `json:"titleLanguage,omitempty" db:"-"`
 }
 
 // BoxOffice 票房信息
@@ -97,12 +108,16 @@ func (b *BoxOffice) Scan(value interface{}) error {
 
 // MovieCreate 创建电影请求
 type MovieCreate struct {
-	Title       string  `json:"title" binding:"required"`
-	ReleaseDate string  `json:"releaseDate" binding:"required"`
-	Genre       string  `json:"genre" binding:"required"`
-	Distributor *string `json:"distributor,omitempty"`
-	Budget      *int64  `json:"budget,omitempty"`
-	MPARating   *string `json:"mpaRating,omitempty"`
+	Title       string   `json:"title" binding:"required"`
+	ReleaseDate string   `json:"releaseDate" binding:"required"`
+	Genre       string   `json:"genre"`
+	Genres      []string `json:"genres,omitempty"`
+	Distributor *string  `json:"distributor,omitempty"`
+	Budget      *int64   `json:"budget,omitempty"`
+	MPARating   *string  `json:"mpaRating,omitempty"`
+
+	// ContentRatings 各分级制度下的级别；mpaRating等同于system为MPA的一项
+	ContentRatings []ContentRatingInput `json:"contentRatings,omitempty"`
 }
 
 // MoviePage 电影分页响应
@@ -0,0 +1,75 @@
+package models
+
+// 演职员角色
+const (
+	RoleDirector        = "director"
+	RoleWriter          = "writer"
+	RoleActor           = "actor"
+	RoleProducer        = "producer"
+	RoleComposer        = "composer"
+	RoleCinematographer = "cinematographer"
+	RoleEditor          = "editor"
+)
+
+// CreditRoles 所有合法的演职员角色
+var CreditRoles = []string{
+	RoleDirector, RoleWriter, RoleActor, RoleProducer,
+	RoleComposer, RoleCinematographer, RoleEditor,
+}
+
+// Person 演职人员模型
+type Person struct {
+	ID        string  `json:"id" db:"id"`
+	Name      string  `json:"name" db:"name"`
+	BirthDate *string `json:"birthDate,omitempty" db:"birth_date"`
+	Biography *string `json:"biography,omitempty" db:"biography"`
+}
+
+// PersonCreate 创建/更新演职人员请求
+type PersonCreate struct {
+	Name      string  `json:"name" binding:"required"`
+	BirthDate *string `json:"birthDate,omitempty"`
+	Biography *string `json:"biography,omitempty"`
+}
+
+// PersonPage 演职人员分页响应
+type PersonPage struct {
+	Items      []Person `json:"items"`
+	NextCursor *string  `json:"nextCursor,omitempty"`
+}
+
+// Credit 电影与演职人员的关联（署名）
+type Credit struct {
+	ID           int64  `json:"id" db:"id"`
+	MovieID      string `json:"movieId" db:"movie_id"`
+	MovieTitle   string `json:"movieTitle" db:"-"`
+	PersonID     string `json:"personId" db:"person_id"`
+	PersonName   string `json:"personName" db:"-"`
+	Role         string `json:"role" db:"role"`
+	Character    string `json:"character,omitempty" db:"character_name"`
+	BillingOrder int    `json:"billingOrder" db:"billing_order"`
+}
+
+// CreditCreate 添加署名请求
+type CreditCreate struct {
+	PersonID     string `json:"personId" binding:"required"`
+	Role         string `json:"role" binding:"required"`
+	Character    string `json:"character,omitempty"`
+	BillingOrder int    `json:"billingOrder"`
+}
+
+// FilmographyEntry 作品年表条目
+type FilmographyEntry struct {
+	MovieID      string `json:"movieId"`
+	MovieTitle   string `json:"movieTitle"`
+	ReleaseDate  string `json:"releaseDate"`
+	Role         string `json:"role"`
+	Character    string `json:"character,omitempty"`
+	BillingOrder int    `json:"billingOrder"`
+}
+
+// Filmography 演职人员作品年表响应
+type Filmography struct {
+	Person Person             `json:"person"`
+	Items  []FilmographyEntry `json:"items"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit 令牌桶限流规则：桶容量为Requests，每个Period补满一次
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimitResult 一次取令牌的结果
+type RateLimitResult struct {
+	Allowed bool
+	Limit   int
+	// Remaining 本次之后桶中剩余的整数令牌
+	Remaining int
+	// Reset 桶补满所需时间
+	Reset time.Duration
+	// RetryAfter 被拒绝时到下一个令牌可用的时间
+	RetryAfter time.Duration
+}
+
+// Take 在桶中有tokens个令牌、距上次更新经过elapsed时尝试取一个令牌，
+// 返回更新后的令牌数和结果。内存和数据库存储共用这一计算
+func (l RateLimit) Take(tokens float64, elapsed time.Duration) (float64, RateLimitResult) {
+	capacity := float64(l.Requests)
+	rate := capacity / l.Period.Seconds()
+
+	if elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed.Seconds()*rate)
+	}
+
+	result := RateLimitResult{Limit: l.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = time.Duration((capacity - tokens) / rate * float64(time.Second))
+	return tokens, result
+}
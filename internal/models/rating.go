@@ -1,5 +1,10 @@
 package models
 
+import (
+	"math"
+	"time"
+)
+
 // Rating 评分模型
 type Rating struct {
 	MovieTitle string  `json:"movieTitle" db:"movie_title"`
@@ -18,10 +23,120 @@ type RatingResult struct {
 	MovieTitle string  `json:"movieTitle"`
 	RaterID    string  `json:"raterId"`
 	Rating     float64 `json:"rating"`
+	// ReviewID 评分附带评论时保存的评论ID，ReviewStatus为评论的审核状态
+	ReviewID     *int64 `json:"reviewId,omitempty"`
+	ReviewStatus string `json:"reviewStatus,omitempty"`
+	// ReviewError 评分已保存但评论保存失败时的说明，此时没有ReviewID
+	ReviewError string `json:"reviewError,omitempty"`
 }
 
 // RatingAggregate 评分聚合响应
 type RatingAggregate struct {
 	Average float64 `json:"average"`
 	Count   int     `json:"count"`
+	// MyRating 已认证调用方自己的评分，匿名访问或未评分时省略
+	MyRating *float64 `json:"myRating,omitempty"`
+	// AsOf 按历史时间点计算时的时间点
+	AsOf *time.Time `json:"asOf,omitempty"`
+}
+
+// 平均评分的舍入方式
+const (
+	RoundingHalfUp   = "half-up"
+	RoundingHalfEven = "half-even"
+)
+
+// ValidRounding 判断舍入方式是否受支持
+func ValidRounding(mode string) bool {
+	return mode == RoundingHalfUp || mode == RoundingHalfEven
+}
+
+// RoundAverage 将平均评分舍入到一位小数。平均值由浮点运算得出，
+// 4.35这类恰好落在中点的值在二进制下可能略小于中点，因此在容差内视为中点再按舍入方式处理
+func RoundAverage(value float64, mode string) float64 {
+	scaled := value * 10
+	floor := math.Floor(scaled)
+	if math.Abs(scaled-floor-0.5) > 1e-9 {
+		return math.Round(scaled) / 10
+	}
+
+	// 中点：half-up向上取整，half-even取最近的偶数
+	if mode == RoundingHalfEven && math.Mod(floor, 2) == 0 {
+		return floor / 10
+	}
+	return (floor + 1) / 10
+}
+
+// Round 按舍入方式舍入聚合中的平均评分
+func (a *RatingAggregate) Round(mode string) {
+	a.Average = RoundAverage(a.Average, mode)
+}
+
+// RaterRating 评分者评分历史中的一条记录
+type RaterRating struct {
+	MovieTitle string    `json:"movieTitle" db:"movie_title"`
+	Rating     float64   `json:"rating" db:"rating"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// RaterRatingPage 评分者评分历史分页响应
+type RaterRatingPage struct {
+	Items      []RaterRating `json:"items"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+}
+
+// RaterGenreStats 评分者在某个类型上的评分统计
+type RaterGenreStats struct {
+	Genre   string  `json:"genre"`
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+}
+
+// RaterStats 评分者评分统计
+type RaterStats struct {
+	RaterID string            `json:"raterId"`
+	Count   int               `json:"count"`
+	Average float64           `json:"average"`
+	Genres  []RaterGenreStats `json:"genres"`
+}
+
+// 评分事件类型
+const (
+	RatingEventCreated = "created"
+	RatingEventUpdated = "updated"
+	RatingEventDeleted = "deleted"
+)
+
+// RatingEvent 评分变更事件
+type RatingEvent struct {
+	ID         int64     `json:"id" db:"id"`
+	MovieTitle string    `json:"movieTitle" db:"movie_title"`
+	RaterID    string    `json:"raterId" db:"rater_id"`
+	EventType  string    `json:"eventType" db:"event_type"`
+	OldRating  *float64  `json:"oldRating" db:"old_rating"`
+	NewRating  *float64  `json:"newRating" db:"new_rating"`
+	OccurredAt time.Time `json:"occurredAt" db:"occurred_at"`
+}
+
+// RatingEventPage 评分事件分页响应
+type RatingEventPage struct {
+	Items      []RatingEvent `json:"items"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+}
+
+// RatingStatsDrift 物化评分聚合与实际评分不一致的电影
+type RatingStatsDrift struct {
+	MovieTitle  string  `json:"movieTitle"`
+	StoredCount int     `json:"storedCount"`
+	StoredSum   float64 `json:"storedSum"`
+	ActualCount int     `json:"actualCount"`
+	ActualSum   float64 `json:"actualSum"`
+}
+
+// RatingStatsReport 评分聚合对账报告
+type RatingStatsReport struct {
+	Drifted  int                `json:"drifted"`
+	Repaired bool               `json:"repaired"`
+	Items    []RatingStatsDrift `json:"items"`
 }
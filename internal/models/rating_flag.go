@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// 可疑评分的标记原因
+const (
+	FlagReasonVelocitySpike   = "velocity_spike"
+	FlagReasonNewRaterCluster = "new_rater_cluster"
+	FlagReasonIdenticalBurst  = "identical_score_burst"
+)
+
+// 标记的审核状态：pending待审核，confirmed确认为刷分，cleared审核通过
+const (
+	FlagStatusPending   = "pending"
+	FlagStatusConfirmed = "confirmed"
+	FlagStatusCleared   = "cleared"
+)
+
+// RatingFlag 可疑评分标记
+type RatingFlag struct {
+	ID         int64      `json:"id" db:"id"`
+	MovieTitle string     `json:"movieTitle" db:"movie_title"`
+	RaterID    string     `json:"raterId" db:"rater_id"`
+	Rating     *float64   `json:"rating" db:"rating"` // 评分已撤回时为null
+	Reason     string     `json:"reason" db:"reason"`
+	Details    string     `json:"details" db:"details"`
+	Status     string     `json:"status" db:"status"`
+	ReviewedBy *string    `json:"reviewedBy,omitempty" db:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty" db:"reviewed_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// RatingFlagPage 可疑评分标记分页响应
+type RatingFlagPage struct {
+	Items      []RatingFlag `json:"items"`
+	NextCursor *string      `json:"nextCursor,omitempty"`
+}
+
+// RatingActivity 检测窗口内的一条评分及评分者首次评分时间
+type RatingActivity struct {
+	RaterID        string
+	Rating         float64
+	SubmittedAt    time.Time
+	RaterFirstSeen time.Time
+}
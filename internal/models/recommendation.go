@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Recommendation 为评分者推荐的一部电影
+type Recommendation struct {
+	Title          string   `json:"title"`
+	ReleaseDate    string   `json:"releaseDate"`
+	Genres         []string `json:"genres"`
+	PredictedScore float64  `json:"predictedScore"`
+	// BecauseYouRated 评分者打过高分、与推荐电影最接近的电影，新评分者没有此字段
+	BecauseYouRated *string `json:"becauseYouRated,omitempty"`
+	Explanation     string  `json:"explanation"`
+}
+
+// RecommendationList 评分者推荐列表响应
+type RecommendationList struct {
+	RaterID        string           `json:"raterId"`
+	Items          []Recommendation `json:"items"`
+	ModelTrainedAt time.Time        `json:"modelTrainedAt"`
+}
+
+// RecommendationCandidate 可推荐给评分者的电影（评分者尚未评分）
+type RecommendationCandidate struct {
+	Title       string   `json:"title"`
+	ReleaseDate string   `json:"releaseDate"`
+	Genres      []string `json:"genres"`
+}
+
+// RecommendationEvaluation 推荐模型在留出集上的评估结果
+type RecommendationEvaluation struct {
+	TrainRatings int     `json:"trainRatings"`
+	TestRatings  int     `json:"testRatings"`
+	Holdout      float64 `json:"holdout"`
+	Seed         int64   `json:"seed"`
+	Factors      int     `json:"factors"`
+	Epochs       int     `json:"epochs"`
+	RMSE         float64 `json:"rmse"`
+	// BaselineRMSE 全部预测为训练集平均分时的RMSE，用于对比
+	BaselineRMSE float64 `json:"baselineRmse"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "regexp"
+
+// 发行渠道
+const (
+	ReleaseChannelTheatrical = "theatrical"
+	ReleaseChannelStreaming  = "streaming"
+	ReleaseChannelPhysical   = "physical"
+)
+
+// ReleaseChannels 支持的发行渠道
+var ReleaseChannels = []string{ReleaseChannelTheatrical, ReleaseChannelStreaming, ReleaseChannelPhysical}
+
+// regionPattern ISO 3166-1 alpha-2地区代码
+var regionPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// ValidRegion 判断地区代码是否为大写的两位ISO 3166-1代码
+func ValidRegion(region string) bool {
+	return regionPattern.MatchString(region)
+}
+
+// ValidReleaseChannel 判断发行渠道是否受支持
+func ValidReleaseChannel(channel string) bool {
+	for _, allowed := range ReleaseChannels {
+		if channel == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// MovieRelease 电影在某地区某渠道的上映记录
+type MovieRelease struct {
+	Region      string  `json:"region"`
+	Channel     string  `json:"channel"`
+	ReleaseDate string  `json:"releaseDate"`
+	Note        *string `json:"note,omitempty"`
+}
+
+// MovieReleaseUpsert 设置上映记录请求
+type MovieReleaseUpsert struct {
+	ReleaseDate string  `json:"releaseDate" binding:"required"`
+	Note        *string `json:"note"`
+}
+
+// CalendarEntry 上映日历中的一条上映记录
+type CalendarEntry struct {
+	MovieID     string   `json:"movieId"`
+	Title       string   `json:"title"`
+	Genres      []string `json:"genres"`
+	Region      string   `json:"region"`
+	Channel     string   `json:"channel"`
+	ReleaseDate string   `json:"releaseDate"`
+}
+
+// CalendarPage 上映日历分页响应
+type CalendarPage struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Items      []CalendarEntry `json:"items"`
+	NextCursor *string         `json:"nextCursor"`
+}
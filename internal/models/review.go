@@ -0,0 +1,126 @@
+package models
+
+import "time"
+
+// MaxReviewLength 评论的最大字符数
+const MaxReviewLength = 5000
+
+// ReviewReactions 允许的表情回应
+var ReviewReactions = []string{"like", "love", "funny", "wow", "sad", "angry"}
+
+// 评论排序方式
+const (
+	ReviewSortRecent  = "recent"
+	ReviewSortHelpful = "helpful"
+)
+
+// 评论审核状态
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+	ReviewStatusHidden   = "hidden"
+)
+
+// ReviewTransitions 审核状态机：每个状态允许转换到的状态。
+// 作者修改评论内容后，无论当前状态如何都回到pending重新审核
+var ReviewTransitions = map[string][]string{
+	ReviewStatusPending:  {ReviewStatusApproved, ReviewStatusRejected, ReviewStatusHidden},
+	ReviewStatusApproved: {ReviewStatusHidden, ReviewStatusRejected, ReviewStatusPending},
+	ReviewStatusHidden:   {ReviewStatusApproved, ReviewStatusRejected},
+	ReviewStatusRejected: {ReviewStatusApproved},
+}
+
+// CanTransitionReview 判断评论能否从一个审核状态转换到另一个
+func CanTransitionReview(from, to string) bool {
+	for _, allowed := range ReviewTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewReportReasons 允许的举报原因
+var ReviewReportReasons = []string{"abuse", "spam", "spoiler", "off-topic", "other"}
+
+// Review 评论及其社区反馈
+type Review struct {
+	ID         int64     `json:"id" db:"id"`
+	MovieTitle string    `json:"movieTitle" db:"movie_title"`
+	RaterID    string    `json:"raterId" db:"rater_id"`
+	Rating     float64   `json:"rating" db:"-"`
+	Body       string    `json:"body" db:"body"`
+	Status     string    `json:"status" db:"status"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+
+	Votes     ReviewVotes    `json:"votes" db:"-"`
+	Reactions map[string]int `json:"reactions" db:"-"`
+	// MyVote 已认证调用方自己的投票，未投票时省略
+	MyVote *bool `json:"myVote,omitempty" db:"-"`
+}
+
+// ReviewVotes 评论的有用/无用票数
+type ReviewVotes struct {
+	Helpful    int `json:"helpful"`
+	NotHelpful int `json:"notHelpful"`
+}
+
+// ReviewVote 对评论投票请求
+type ReviewVote struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+// ReviewPage 评论分页响应
+type ReviewPage struct {
+	Items      []Review `json:"items"`
+	NextCursor *string  `json:"nextCursor,omitempty"`
+}
+
+// ReviewModerationEvent 一次审核决定
+type ReviewModerationEvent struct {
+	ID         int64     `json:"id" db:"id"`
+	ReviewID   int64     `json:"reviewId" db:"review_id"`
+	FromStatus *string   `json:"fromStatus" db:"from_status"`
+	ToStatus   string    `json:"toStatus" db:"to_status"`
+	Moderator  string    `json:"moderator" db:"moderator"`
+	Reason     string    `json:"reason" db:"reason"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ReviewModerationDecision 版主审核决定请求
+type ReviewModerationDecision struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ReviewReport 用户对评论的举报
+type ReviewReport struct {
+	ID         int64      `json:"id" db:"id"`
+	ReviewID   int64      `json:"reviewId" db:"review_id"`
+	ReporterID string     `json:"reporterId" db:"reporter_id"`
+	Reason     string     `json:"reason" db:"reason"`
+	Details    string     `json:"details" db:"details"`
+	Status     string     `json:"status" db:"status"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" db:"resolved_at"`
+}
+
+// ReviewReportCreate 举报评论请求
+type ReviewReportCreate struct {
+	Reason  string `json:"reason" binding:"required"`
+	Details string `json:"details"`
+}
+
+// ModerationQueueItem 审核队列中的评论及其未处理的举报数
+type ModerationQueueItem struct {
+	Review
+	OpenReports int `json:"openReports"`
+}
+
+// ModerationQueuePage 审核队列分页响应
+type ModerationQueuePage struct {
+	Items      []ModerationQueueItem `json:"items"`
+	NextCursor *string               `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// 访问控制角色（与演职人员的RoleEditor等职务区分）
+const (
+	UserRoleRater     = "rater"
+	UserRoleEditor    = "editor"
+	UserRoleModerator = "moderator"
+	UserRoleAdmin     = "admin"
+)
+
+// 权限，由角色授予
+const (
+	PermRatingsWrite       = "ratings:write"
+	PermRatingsImpersonate = "ratings:impersonate"
+	PermRatingsModerate    = "ratings:moderate"
+	PermMoviesWrite        = "movies:write"
+	PermMoviesDelete       = "movies:delete"
+	PermKeysManage         = "keys:manage"
+	PermRolesManage        = "roles:manage"
+	PermDataExport         = "data:export"
+	PermListsWrite         = "lists:write"
+	PermReviewsModerate    = "reviews:moderate"
+)
+
+// PermissionScopes 每项权限要求凭证具有的权限范围，角色授予的权限仍受凭证范围限制；
+// 未列出的权限不要求特定范围
+var PermissionScopes = map[string]string{
+	PermRatingsWrite:       ScopeRatingsWrite,
+	PermRatingsImpersonate: ScopeAdmin,
+	PermRatingsModerate:    ScopeAdmin,
+	PermMoviesWrite:        ScopeMoviesWrite,
+	PermMoviesDelete:       ScopeMoviesWrite,
+	PermKeysManage:         ScopeAdmin,
+	PermRolesManage:        ScopeAdmin,
+	PermDataExport:         ScopeDataExport,
+	PermListsWrite:         ScopeRatingsWrite,
+	PermReviewsModerate:    ScopeAdmin,
+}
+
+// Role 角色及其授予的权限
+type Role struct {
+	Name        string   `json:"name" db:"name"`
+	Description string   `json:"description" db:"description"`
+	Permissions []string `json:"permissions" db:"-"`
+}
+
+// RoleAssignment 主体（评分者/用户）被授予的角色
+type RoleAssignment struct {
+	Subject   string    `json:"subject" db:"subject"`
+	Role      string    `json:"role" db:"role"`
+	GrantedAt time.Time `json:"grantedAt" db:"granted_at"`
+}
+
+// AuthzDecision 授权判定结果，拒绝时用于审计日志和错误响应
+type AuthzDecision struct {
+	Allowed    bool     `json:"allowed"`
+	Permission string   `json:"permission"`
+	Subject    string   `json:"subject,omitempty"`
+	Roles      []string `json:"roles"`
+	Reason     string   `json:"reason,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// 相似电影的计算依据
+const (
+	SimilarityBasisRatings    = "ratings"
+	SimilarityBasisAttributes = "attributes"
+)
+
+// MovieSimilarity 两部电影之间基于评分的相似度
+type MovieSimilarity struct {
+	MovieTitle   string  `json:"movieTitle" db:"movie_title"`
+	SimilarTitle string  `json:"similarTitle" db:"similar_title"`
+	Score        float64 `json:"score" db:"score"`
+	CommonRaters int     `json:"commonRaters" db:"common_raters"`
+}
+
+// SimilarMovie 相似电影响应项
+type SimilarMovie struct {
+	Title        string   `json:"title"`
+	ReleaseDate  string   `json:"releaseDate"`
+	Genres       []string `json:"genres"`
+	Score        float64  `json:"score"`
+	CommonRaters int      `json:"commonRaters,omitempty"`
+	// Basis 相似度依据：ratings为协同过滤，attributes为类型和发行商（冷启动回退）
+	Basis string `json:"basis"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxTagLength 标签的最大长度（字符数）
+const MaxTagLength = 50
+
+// Tag 标签及其使用情况
+type Tag struct {
+	Name string `json:"name"`
+	// MovieCount 使用该标签的电影数，UseCount 标签被添加的总次数（每位评分者每部电影计一次）
+	MovieCount int `json:"movieCount"`
+	UseCount   int `json:"useCount"`
+}
+
+// TagPage 标签分页响应
+type TagPage struct {
+	Items      []Tag   `json:"items"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// MovieTag 电影上的标签及添加该标签的评分者数
+type MovieTag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Applied 调用方是否添加过该标签，匿名请求时省略
+	Applied *bool `json:"applied,omitempty"`
+}
+
+// MovieTagAdd 为电影添加标签请求
+type MovieTagAdd struct {
+	Tag string `json:"tag" binding:"required"`
+}
+
+// NormalizeTag 规范化标签写法：小写、首尾去空白、连续空白合并为一个空格
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// TagKey 计算标签的归一化键：小写并去掉所有非字母数字字符，
+// 使 "Mind-Bending"、"mind bending" 得到相同的键
+func TagKey(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// AliasRepository 电影别名存储库接口
+type AliasRepository interface {
+	Create(alias *models.MovieAlias) error
+	ListByMovie(movieID string) ([]models.MovieAlias, error)
+	ListByMovies(movieIDs []string) (map[string][]models.MovieAlias, error)
+	Delete(movieID string, aliasID int64) (bool, error)
+}
+
+// aliasRepository 电影别名存储库实现
+type aliasRepository struct {
+	db *sql.DB
+}
+
+// NewAliasRepository 创建电影别名存储库实例
+func NewAliasRepository(db *sql.DB) AliasRepository {
+	return &aliasRepository{db: db}
+}
+
+// Create 创建别名
+func (r *aliasRepository) Create(alias *models.MovieAlias) error {
+	query := `
+		INSERT INTO movie_aliases (movie_id, title, language)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(query, alias.MovieID, alias.Title, alias.Language).Scan(&alias.ID)
+}
+
+// ListByMovie 获取电影的所有别名
+func (r *aliasRepository) ListByMovie(movieID string) ([]models.MovieAlias, error) {
+	byMovie, err := r.ListByMovies([]string{movieID})
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := byMovie[movieID]
+	if aliases == nil {
+		aliases = []models.MovieAlias{}
+	}
+	return aliases, nil
+}
+
+// ListByMovies 批量获取多部电影的别名，按电影ID分组
+func (r *aliasRepository) ListByMovies(movieIDs []string) (map[string][]models.MovieAlias, error) {
+	result := make(map[string][]models.MovieAlias)
+	if len(movieIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, movie_id, title, language
+		FROM movie_aliases
+		WHERE movie_id = ANY($1)
+		ORDER BY movie_id, language, id
+	`
+
+	rows, err := r.db.Query(query, pq.Array(movieIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias models.MovieAlias
+		if err := rows.Scan(&alias.ID, &alias.MovieID, &alias.Title, &alias.Language); err != nil {
+			return nil, err
+		}
+		result[alias.MovieID] = append(result[alias.MovieID], alias)
+	}
+
+	return result, rows.Err()
+}
+
+// Delete 删除别名，返回是否有记录被删除
+func (r *aliasRepository) Delete(movieID string, aliasID int64) (bool, error) {
+	query := `DELETE FROM movie_aliases WHERE movie_id = $1 AND id = $2`
+
+	res, err := r.db.Exec(query, movieID, aliasID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
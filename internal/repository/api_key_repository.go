@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyRepository API密钥存储库接口
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByHash(keyHash string) (*models.APIKey, error)
+	List() ([]models.APIKey, error)
+	Revoke(id string) (bool, error)
+	TouchLastUsed(id string) error
+}
+
+// apiKeyRepository API密钥存储库实现
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository 创建API密钥存储库实例
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// apiKeyColumns 查询API密钥时使用的列
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, subject, expires_at, last_used_at, revoked_at, created_at"
+
+// scanAPIKey 扫描一行API密钥记录
+func scanAPIKey(scanner interface{ Scan(...interface{}) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	err := scanner.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes),
+		&key.Subject, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create 创建API密钥
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, subject, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(query, key.ID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes),
+		key.Subject, key.ExpiresAt).Scan(&key.CreatedAt)
+}
+
+// GetByHash 根据密钥哈希获取API密钥
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	row := r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", keyHash)
+
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// List 列出所有API密钥（包括已吊销的）
+func (r *apiKeyRepository) List() ([]models.APIKey, error) {
+	rows, err := r.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke 吊销API密钥，返回是否有未吊销的密钥被吊销
+func (r *apiKeyRepository) Revoke(id string) (bool, error) {
+	res, err := r.db.Exec(`UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// TouchLastUsed 更新最后使用时间，一分钟内的重复使用不再写库
+func (r *apiKeyRepository) TouchLastUsed(id string) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')
+	`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// CollectionRepository 电影合集存储库接口
+type CollectionRepository interface {
+	Create(collection *models.Collection) error
+	GetByID(id string) (*models.Collection, error)
+	List(limit int, cursor string) (*models.CollectionPage, error)
+	Delete(id string) (bool, error)
+	AddMember(collectionID, movieID string, position *int) (int, error)
+	RemoveMember(collectionID, movieID string) (bool, error)
+	ListMembers(collectionID string) ([]models.CollectionMember, error)
+}
+
+// collectionRepository 电影合集存储库实现
+type collectionRepository struct {
+	db *sql.DB
+}
+
+// NewCollectionRepository 创建电影合集存储库实例
+func NewCollectionRepository(db *sql.DB) CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+// Create 创建合集
+func (r *collectionRepository) Create(collection *models.Collection) error {
+	query := `
+		INSERT INTO collections (id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(query, collection.ID, collection.Name, collection.Description).Scan(&collection.ID)
+}
+
+// GetByID 根据ID获取合集
+func (r *collectionRepository) GetByID(id string) (*models.Collection, error) {
+	query := `SELECT id, name, description FROM collections WHERE id = $1`
+
+	var collection models.Collection
+	err := r.db.QueryRow(query, id).Scan(&collection.ID, &collection.Name, &collection.Description)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// List 分页列出合集
+func (r *collectionRepository) List(limit int, cursor string) (*models.CollectionPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT id, name, description
+		FROM collections
+		ORDER BY name ASC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []models.Collection{}
+	for rows.Next() {
+		var collection models.Collection
+		if err := rows.Scan(&collection.ID, &collection.Name, &collection.Description); err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.CollectionPage{Items: collections}
+	if len(collections) > limit {
+		result.Items = collections[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// Delete 删除合集（成员关系随外键级联删除），返回是否有记录被删除
+func (r *collectionRepository) Delete(id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM collections WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddMember 将电影加入合集，未指定位置时追加到末尾；电影已在合集中时更新其位置。
+// 返回电影在合集中的位置
+func (r *collectionRepository) AddMember(collectionID, movieID string, position *int) (int, error) {
+	query := `
+		INSERT INTO collection_movies (collection_id, movie_id, position)
+		VALUES ($1, $2, COALESCE($3, (SELECT COALESCE(MAX(position), 0) + 1 FROM collection_movies WHERE collection_id = $1)))
+		ON CONFLICT (collection_id, movie_id)
+		DO UPDATE SET position = COALESCE($3, collection_movies.position)
+		RETURNING position
+	`
+
+	var result int
+	err := r.db.QueryRow(query, collectionID, movieID, position).Scan(&result)
+	return result, err
+}
+
+// RemoveMember 将电影移出合集，返回是否有记录被删除
+func (r *collectionRepository) RemoveMember(collectionID, movieID string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM collection_movies WHERE collection_id = $1 AND movie_id = $2`, collectionID, movieID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListMembers 按位置顺序获取合集成员及其评分聚合
+func (r *collectionRepository) ListMembers(collectionID string) ([]models.CollectionMember, error) {
+	query := `
+		SELECT cm.position,
+		       m.id, m.title, m.release_date, m.genre, m.distributor, m.budget, m.box_office,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = m.id ORDER BY mg.position),
+		       ` + contentRatingsColumn("m.id") + `,
+		       COALESCE(AVG(r.rating), 0), COUNT(r.rating)
+		FROM collection_movies cm
+		JOIN movies m ON m.id = cm.movie_id
+		LEFT JOIN ratings r ON r.movie_title = m.title
+		WHERE cm.collection_id = $1
+		GROUP BY cm.position, m.id
+		ORDER BY cm.position ASC, m.release_date ASC
+	`
+
+	rows, err := r.db.Query(query, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.CollectionMember{}
+	for rows.Next() {
+		var member models.CollectionMember
+		var boxOfficeJSON sql.NullString
+		var contentRatingsJSON []byte
+
+		err := rows.Scan(
+			&member.Position,
+			&member.Movie.ID, &member.Movie.Title, &member.Movie.ReleaseDate, &member.Movie.Genre,
+			&member.Movie.Distributor, &member.Movie.Budget, &boxOfficeJSON,
+			pq.Array(&member.Movie.Genres), &contentRatingsJSON,
+			&member.Rating.Average, &member.Rating.Count,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyContentRatings(&member.Movie, contentRatingsJSON); err != nil {
+			return nil, err
+		}
+
+		// 解析box_office JSON
+		member.Movie.BoxOffice = parseBoxOffice(boxOfficeJSON)
+
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
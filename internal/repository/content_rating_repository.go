@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"movie-rating-api/internal/models"
+)
+
+// ContentRatingRepository 内容分级存储库接口
+type ContentRatingRepository interface {
+	ListSystems() ([]models.ContentRatingSystem, error)
+	GetSystem(code string) (*models.ContentRatingSystem, error)
+	ListByMovie(movieID string) ([]models.ContentRating, error)
+	Set(movieID, system, rating string) (bool, error)
+	Delete(movieID, system string) (bool, error)
+}
+
+// contentRatingRepository 内容分级存储库实现
+type contentRatingRepository struct {
+	db *sql.DB
+}
+
+// NewContentRatingRepository 创建内容分级存储库实例
+func NewContentRatingRepository(db *sql.DB) ContentRatingRepository {
+	return &contentRatingRepository{db: db}
+}
+
+// contentRatingsColumn 返回以JSON数组读取电影全部分级的子查询，movieID为电影ID列的SQL表达式
+func contentRatingsColumn(movieID string) string {
+	return `COALESCE((SELECT json_agg(json_build_object('system', cr.system, 'region', s.region, 'rating', cr.rating, 'minAge', l.min_age) ORDER BY s.position)
+		FROM movie_content_ratings cr
+		JOIN content_rating_systems s ON s.code = cr.system
+		JOIN content_rating_levels l ON l.system = cr.system AND l.code = cr.rating
+		WHERE cr.movie_id = ` + movieID + `), '[]')`
+}
+
+// applyContentRatings 解析contentRatingsColumn读取的分级，并填充兼容字段MPARating
+func applyContentRatings(movie *models.Movie, ratingsJSON []byte) error {
+	movie.ContentRatings = []models.ContentRating{}
+	if err := json.Unmarshal(ratingsJSON, &movie.ContentRatings); err != nil {
+		return err
+	}
+
+	movie.MPARating = nil
+	for _, rating := range movie.ContentRatings {
+		if rating.System == models.ContentRatingSystemMPA {
+			mpaRating := rating.Rating
+			movie.MPARating = &mpaRating
+		}
+	}
+	return nil
+}
+
+// insertContentRatings 在事务中写入电影的分级
+func insertContentRatings(tx *sql.Tx, movieID string, ratings []models.ContentRating) error {
+	for _, rating := range ratings {
+		_, err := tx.Exec(`
+			INSERT INTO movie_content_ratings (movie_id, system, rating) VALUES ($1, $2, $3)
+		`, movieID, rating.System, rating.Rating)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSystems 按顺序列出所有分级制度及其级别
+func (r *contentRatingRepository) ListSystems() ([]models.ContentRatingSystem, error) {
+	query := `
+		SELECT s.code, s.region, s.name, l.code, l.min_age
+		FROM content_rating_systems s
+		JOIN content_rating_levels l ON l.system = s.code
+		ORDER BY s.position ASC, s.code ASC, l.position ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	systems := []models.ContentRatingSystem{}
+	for rows.Next() {
+		var system models.ContentRatingSystem
+		var level models.ContentRatingLevel
+		if err := rows.Scan(&system.Code, &system.Region, &system.Name, &level.Code, &level.MinAge); err != nil {
+			return nil, err
+		}
+
+		// 结果按制度排序，同一制度的级别相邻
+		if n := len(systems); n > 0 && systems[n-1].Code == system.Code {
+			systems[n-1].Ratings = append(systems[n-1].Ratings, level)
+			continue
+		}
+		system.Ratings = []models.ContentRatingLevel{level}
+		systems = append(systems, system)
+	}
+
+	return systems, rows.Err()
+}
+
+// GetSystem 根据代码（不区分大小写）获取分级制度及其级别，未找到时返回nil
+func (r *contentRatingRepository) GetSystem(code string) (*models.ContentRatingSystem, error) {
+	var system models.ContentRatingSystem
+	err := r.db.QueryRow(`
+		SELECT code, region, name FROM content_rating_systems WHERE UPPER(code) = UPPER($1)
+	`, code).Scan(&system.Code, &system.Region, &system.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT code, min_age FROM content_rating_levels WHERE system = $1 ORDER BY position ASC
+	`, system.Code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	system.Ratings = []models.ContentRatingLevel{}
+	for rows.Next() {
+		var level models.ContentRatingLevel
+		if err := rows.Scan(&level.Code, &level.MinAge); err != nil {
+			return nil, err
+		}
+		system.Ratings = append(system.Ratings, level)
+	}
+
+	return &system, rows.Err()
+}
+
+// ListByMovie 按分级制度顺序获取电影的分级
+func (r *contentRatingRepository) ListByMovie(movieID string) ([]models.ContentRating, error) {
+	query := `
+		SELECT cr.system, s.region, cr.rating, l.min_age
+		FROM movie_content_ratings cr
+		JOIN content_rating_systems s ON s.code = cr.system
+		JOIN content_rating_levels l ON l.system = cr.system AND l.code = cr.rating
+		WHERE cr.movie_id = $1
+		ORDER BY s.position ASC
+	`
+
+	rows, err := r.db.Query(query, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []models.ContentRating{}
+	for rows.Next() {
+		var rating models.ContentRating
+		if err := rows.Scan(&rating.System, &rating.Region, &rating.Rating, &rating.MinAge); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+// Set 设置电影在某分级制度下的级别，返回是否新建了记录
+func (r *contentRatingRepository) Set(movieID, system, rating string) (bool, error) {
+	query := `
+		INSERT INTO movie_content_ratings (movie_id, system, rating)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (movie_id, system)
+		DO UPDATE SET rating = EXCLUDED.rating, updated_at = CURRENT_TIMESTAMP
+		RETURNING (xmax = 0)
+	`
+
+	var created bool
+	err := r.db.QueryRow(query, movieID, system, rating).Scan(&created)
+	return created, err
+}
+
+// Delete 删除电影在某分级制度下的级别，返回是否有记录被删除
+func (r *contentRatingRepository) Delete(movieID, system string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM movie_content_ratings WHERE movie_id = $1 AND system = $2
+	`, movieID, system)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+)
+
+// CreditRepository 电影署名存储库接口
+type CreditRepository interface {
+	Create(credit *models.Credit) error
+	ListByMovie(movieID string) ([]models.Credit, error)
+	ListByPerson(personID string) ([]models.FilmographyEntry, error)
+	Delete(movieID string, creditID int64) (bool, error)
+}
+
+// creditRepository 电影署名存储库实现
+type creditRepository struct {
+	db *sql.DB
+}
+
+// NewCreditRepository 创建电影署名存储库实例
+func NewCreditRepository(db *sql.DB) CreditRepository {
+	return &creditRepository{db: db}
+}
+
+// Create 添加署名
+func (r *creditRepository) Create(credit *models.Credit) error {
+	query := `
+		INSERT INTO movie_credits (movie_id, person_id, role, character_name, billing_order)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(query, credit.MovieID, credit.PersonID, credit.Role,
+		credit.Character, credit.BillingOrder).Scan(&credit.ID)
+}
+
+// ListByMovie 获取电影的演职员表，按署名顺序排列
+func (r *creditRepository) ListByMovie(movieID string) ([]models.Credit, error) {
+	query := `
+		SELECT c.id, c.movie_id, m.title, c.person_id, p.name, c.role, c.character_name, c.billing_order
+		FROM movie_credits c
+		JOIN movies m ON m.id = c.movie_id
+		JOIN people p ON p.id = c.person_id
+		WHERE c.movie_id = $1
+		ORDER BY c.billing_order ASC, c.id ASC
+	`
+
+	rows, err := r.db.Query(query, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := []models.Credit{}
+	for rows.Next() {
+		var credit models.Credit
+		if err := rows.Scan(&credit.ID, &credit.MovieID, &credit.MovieTitle, &credit.PersonID,
+			&credit.PersonName, &credit.Role, &credit.Character, &credit.BillingOrder); err != nil {
+			return nil, err
+		}
+		credits = append(credits, credit)
+	}
+
+	return credits, rows.Err()
+}
+
+// ListByPerson 获取演职人员的作品年表，按上映日期倒序排列
+func (r *creditRepository) ListByPerson(personID string) ([]models.FilmographyEntry, error) {
+	query := `
+		SELECT m.id, m.title, TO_CHAR(m.release_date, 'YYYY-MM-DD'), c.role, c.character_name, c.billing_order
+		FROM movie_credits c
+		JOIN movies m ON m.id = c.movie_id
+		WHERE c.person_id = $1
+		ORDER BY m.release_date DESC, m.title ASC, c.billing_order ASC
+	`
+
+	rows, err := r.db.Query(query, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.FilmographyEntry{}
+	for rows.Next() {
+		var entry models.FilmographyEntry
+		if err := rows.Scan(&entry.MovieID, &entry.MovieTitle, &entry.ReleaseDate,
+			&entry.Role, &entry.Character, &entry.BillingOrder); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Delete 删除署名，返回是否有记录被删除
+func (r *creditRepository) Delete(movieID string, creditID int64) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM movie_credits WHERE movie_id = $1 AND id = $2`, movieID, creditID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// offsetCursor 基于偏移量的分页游标，序列化为base64编码的JSON（如 {"offset":200}）
+type offsetCursor struct {
+	Offset int `json:"offset"`
+}
+
+// encodeOffsetCursor 编码分页游标
+func encodeOffsetCursor(offset int) string {
+	data, _ := json.Marshal(offsetCursor{Offset: offset})
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeOffsetCursor 解码分页游标，无效游标视为从头开始
+func decodeOffsetCursor(cursor string) int {
+	if cursor == "" {
+		return 0
+	}
+
+	data, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0
+	}
+
+	var c offsetCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return 0
+	}
+	return c.Offset
+}
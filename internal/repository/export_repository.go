@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"movie-rating-api/internal/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ExportRepository 数据导出存储库接口，逐行回调而不在内存中缓存整张表
+type ExportRepository interface {
+	StreamMovies(query map[string]interface{}, fn func(*models.MovieExport) error) error
+	StreamRatings(movieTitle, raterID string, fn func(*models.RatingExport) error) error
+}
+
+// exportRepository 数据导出存储库实现
+type exportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository 创建数据导出存储库实例
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// StreamMovies 按List的过滤和排序规则逐行读取电影及其评分聚合，
+// 结果集在遍历时从数据库连接上逐步读取
+func (r *exportRepository) StreamMovies(query map[string]interface{}, fn func(*models.MovieExport) error) error {
+	orderBy, err := movieListOrder(query)
+	if err != nil {
+		return err
+	}
+
+	conditions, args := buildMovieFilters(query)
+
+	sqlQuery := `
+		SELECT id, title, TO_CHAR(release_date, 'YYYY-MM-DD'), genre, distributor, budget, box_office,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = movies.id ORDER BY mg.position),
+		       ` + contentRatingsColumn("movies.id") + `,
+		       COALESCE(stats.rating_avg, 0), COALESCE(stats.rating_count, 0)
+		FROM movies
+		LEFT JOIN movie_rating_stats stats ON stats.movie_title = movies.title`
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += " ORDER BY " + orderBy
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var export models.MovieExport
+		var boxOfficeJSON sql.NullString
+		var contentRatingsJSON []byte
+
+		err := rows.Scan(
+			&export.ID, &export.Title, &export.ReleaseDate, &export.Genre,
+			&export.Distributor, &export.Budget, &boxOfficeJSON,
+			pq.Array(&export.Genres), &contentRatingsJSON,
+			&export.Rating.Average, &export.Rating.Count,
+		)
+		if err != nil {
+			return err
+		}
+		if err := applyContentRatings(&export.Movie, contentRatingsJSON); err != nil {
+			return err
+		}
+
+		// 解析box_office JSON
+		export.BoxOffice = parseBoxOffice(boxOfficeJSON)
+
+		if err := fn(&export); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// StreamRatings 逐行读取原始评分，可按电影标题和评分者过滤
+func (r *exportRepository) StreamRatings(movieTitle, raterID string, fn func(*models.RatingExport) error) error {
+	var conditions []string
+	var args []interface{}
+
+	if movieTitle != "" {
+		args = append(args, movieTitle)
+		conditions = append(conditions, fmt.Sprintf("movie_title = $%d", len(args)))
+	}
+	if raterID != "" {
+		args = append(args, raterID)
+		conditions = append(conditions, fmt.Sprintf("rater_id = $%d", len(args)))
+	}
+
+	sqlQuery := "SELECT movie_title, rater_id, rating, created_at, updated_at FROM ratings"
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += " ORDER BY movie_title ASC, rater_id ASC"
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var export models.RatingExport
+		if err := rows.Scan(&export.MovieTitle, &export.RaterID, &export.Rating, &export.CreatedAt, &export.UpdatedAt); err != nil {
+			return err
+		}
+		if err := fn(&export); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+)
+
+// GenreRepository 电影类型存储库接口
+type GenreRepository interface {
+	Create(genre *models.Genre, synonyms []string) error
+	Resolve(name string) (*models.Genre, error)
+	ListWithCounts() ([]models.Genre, error)
+}
+
+// genreRepository 电影类型存储库实现
+type genreRepository struct {
+	db *sql.DB
+}
+
+// NewGenreRepository 创建电影类型存储库实例
+func NewGenreRepository(db *sql.DB) GenreRepository {
+	return &genreRepository{db: db}
+}
+
+// Create 创建类型及其同义词
+func (r *genreRepository) Create(genre *models.Genre, synonyms []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO genres (slug, name, key)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	if err := tx.QueryRow(query, genre.Slug, genre.Name, models.GenreKey(genre.Name)).Scan(&genre.ID); err != nil {
+		return err
+	}
+
+	for _, synonym := range synonyms {
+		key := models.GenreKey(synonym)
+		if key == "" || key == models.GenreKey(genre.Name) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO genre_synonyms (key, genre_id) VALUES ($1, $2)`, key, genre.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Resolve 根据名称、slug或同义词解析标准类型，未找到时返回nil。
+// 同一名称匹配多个类型时依次优先名称、slug、同义词，再按ID，保证结果确定
+func (r *genreRepository) Resolve(name string) (*models.Genre, error) {
+	query := `
+		SELECT g.id, g.slug, g.name
+		FROM genres g
+		WHERE g.key = $1
+		   OR g.slug = LOWER($2)
+		   OR g.id IN (SELECT genre_id FROM genre_synonyms WHERE key = $1)
+		ORDER BY (g.key = $1) DESC, (g.slug = LOWER($2)) DESC, g.id ASC
+		LIMIT 1
+	`
+
+	var genre models.Genre
+	err := r.db.QueryRow(query, models.GenreKey(name), name).Scan(&genre.ID, &genre.Slug, &genre.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &genre, nil
+}
+
+// ListWithCounts 列出所有类型及其电影数量
+func (r *genreRepository) ListWithCounts() ([]models.Genre, error) {
+	query := `
+		SELECT g.id, g.slug, g.name, COUNT(mg.movie_id)
+		FROM genres g
+		LEFT JOIN movie_genres mg ON mg.genre_id = g.id
+		GROUP BY g.id, g.slug, g.name
+		ORDER BY g.name ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var genre models.Genre
+		if err := rows.Scan(&genre.ID, &genre.Slug, &genre.Name, &genre.MovieCount); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	return genres, rows.Err()
+}
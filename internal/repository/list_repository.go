@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+)
+
+// ListRepository 评分者电影列表存储库接口
+type ListRepository interface {
+	Create(list *models.UserList) error
+	EnsureWatchlist(list *models.UserList) (*models.UserList, error)
+	GetByID(id string) (*models.UserList, error)
+	GetWatchlist(ownerID string) (*models.UserList, error)
+	List(ownerID, viewerID string, limit int, cursor string) (*models.UserListPage, error)
+	Update(list *models.UserList) error
+	Delete(id string) (bool, error)
+	AddItem(listID, movieID string, position *int, note *string) error
+	RemoveItem(listID, movieID string) (bool, error)
+	ListItems(listID string, limit int, cursor string) (*models.UserListItemPage, error)
+	MarkWatched(ownerID, movieTitle string) (bool, error)
+}
+
+// listRepository 评分者电影列表存储库实现
+type listRepository struct {
+	db *sql.DB
+}
+
+// NewListRepository 创建评分者电影列表存储库实例
+func NewListRepository(db *sql.DB) ListRepository {
+	return &listRepository{db: db}
+}
+
+// userListColumns 查询列表时的列，包括电影数
+const userListColumns = `
+	l.id, l.owner_id, l.name, l.description, l.kind, l.visibility, l.created_at, l.updated_at,
+	(SELECT COUNT(*) FROM user_list_items i WHERE i.list_id = l.id)
+`
+
+// scanUserList 扫描一行列表数据
+func scanUserList(scanner interface{ Scan(...interface{}) error }) (*models.UserList, error) {
+	var list models.UserList
+	err := scanner.Scan(
+		&list.ID, &list.OwnerID, &list.Name, &list.Description, &list.Kind, &list.Visibility,
+		&list.CreatedAt, &list.UpdatedAt, &list.ItemCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Create 创建列表
+func (r *listRepository) Create(list *models.UserList) error {
+	query := `
+		INSERT INTO user_lists (id, owner_id, name, description, kind, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(query, list.ID, list.OwnerID, list.Name, list.Description, list.Kind, list.Visibility).
+		Scan(&list.CreatedAt, &list.UpdatedAt)
+}
+
+// EnsureWatchlist 评分者还没有待看列表时创建，已有时返回现有的待看列表
+func (r *listRepository) EnsureWatchlist(list *models.UserList) (*models.UserList, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO user_lists (id, owner_id, name, kind, visibility)
+		VALUES ($1, $2, $3, 'watchlist', $4)
+		ON CONFLICT (owner_id) WHERE kind = 'watchlist' DO NOTHING
+	`, list.ID, list.OwnerID, list.Name, list.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetWatchlist(list.OwnerID)
+}
+
+// GetByID 根据ID获取列表
+func (r *listRepository) GetByID(id string) (*models.UserList, error) {
+	list, err := scanUserList(r.db.QueryRow(`SELECT `+userListColumns+` FROM user_lists l WHERE l.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return list, err
+}
+
+// GetWatchlist 获取评分者的待看列表
+func (r *listRepository) GetWatchlist(ownerID string) (*models.UserList, error) {
+	list, err := scanUserList(r.db.QueryRow(
+		`SELECT `+userListColumns+` FROM user_lists l WHERE l.owner_id = $1 AND l.kind = 'watchlist'`, ownerID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return list, err
+}
+
+// List 按最近更新时间分页列出列表：公开列表，以及查看者自己的私有列表；ownerID非空时只列出该评分者的列表
+func (r *listRepository) List(ownerID, viewerID string, limit int, cursor string) (*models.UserListPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT ` + userListColumns + `
+		FROM user_lists l
+		WHERE ($1 = '' OR l.owner_id = $1)
+		  AND (l.visibility = 'public' OR ($2 <> '' AND l.owner_id = $2))
+		ORDER BY l.updated_at DESC, l.id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, ownerID, viewerID, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []models.UserList{}
+	for rows.Next() {
+		list, err := scanUserList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, *list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.UserListPage{Items: lists}
+	if len(lists) > limit {
+		result.Items = lists[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// Update 更新列表名称、描述和可见性
+func (r *listRepository) Update(list *models.UserList) error {
+	query := `
+		UPDATE user_lists
+		SET name = $2, description = $3, visibility = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(query, list.ID, list.Name, list.Description, list.Visibility).Scan(&list.UpdatedAt)
+}
+
+// Delete 删除列表（列表中的电影随外键级联删除），返回是否有记录被删除
+func (r *listRepository) Delete(id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM user_lists WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddItem 将电影加入列表，未指定位置时追加到末尾；电影已在列表中时更新位置和备注
+func (r *listRepository) AddItem(listID, movieID string, position *int, note *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO user_list_items (list_id, movie_id, position, note)
+		VALUES ($1, $2, COALESCE($3, (SELECT COALESCE(MAX(position), 0) + 1 FROM user_list_items WHERE list_id = $1)), $4)
+		ON CONFLICT (list_id, movie_id)
+		DO UPDATE SET position = COALESCE($3, user_list_items.position), note = COALESCE($4, user_list_items.note)
+	`, listID, movieID, position, note)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE user_lists SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, listID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveItem 将电影移出列表，返回是否有记录被删除
+func (r *listRepository) RemoveItem(listID, movieID string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM user_list_items WHERE list_id = $1 AND movie_id = $2`, listID, movieID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE user_lists SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, listID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ListItems 按位置顺序分页获取列表中的电影
+func (r *listRepository) ListItems(listID string, limit int, cursor string) (*models.UserListItemPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT i.position, m.title, m.release_date, i.note, i.added_at, i.watched_at
+		FROM user_list_items i
+		JOIN movies m ON m.id = i.movie_id
+		WHERE i.list_id = $1
+		ORDER BY i.position ASC, i.added_at ASC, m.title ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, listID, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.UserListItem{}
+	for rows.Next() {
+		var item models.UserListItem
+		if err := rows.Scan(&item.Position, &item.MovieTitle, &item.ReleaseDate, &item.Note, &item.AddedAt, &item.WatchedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.UserListItemPage{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// MarkWatched 将评分者待看列表中的电影标记为已看，返回是否有电影被标记
+func (r *listRepository) MarkWatched(ownerID, movieTitle string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE user_list_items i
+		SET watched_at = CURRENT_TIMESTAMP
+		FROM user_lists l, movies m
+		WHERE i.list_id = l.id AND l.owner_id = $1 AND l.kind = 'watchlist'
+		  AND i.movie_id = m.id AND m.title = $2
+		  AND i.watched_at IS NULL
+	`, ownerID, movieTitle)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"movie-rating-api/internal/models"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 // MovieRepository 电影存储库接口
 type MovieRepository interface {
 	Create(movie *models.Movie) error
+	CreateBatch(movies []*models.Movie, atomic bool) ([]error, error)
+	ExistingTitles(titles []string) (map[string]bool, error)
 	GetByTitle(title string) (*models.Movie, error)
 	List(query map[string]interface{}, limit int, cursor string) (*models.MoviePage, error)
 	Update(movie *models.Movie) error
@@ -26,34 +30,138 @@ func NewMovieRepository(db *sql.DB) MovieRepository {
 	return &movieRepository{db: db}
 }
 
-// Create 创建新电影
+// Create 创建新电影，并在同一事务中写入电影类型关联
 func (r *movieRepository) Create(movie *models.Movie) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertMovie(tx, movie); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateBatch 在一个事务中批量创建电影，返回与movies一一对应的错误列表。
+// atomic为true时遇到第一个错误即回滚整个事务；否则每行使用独立的保存点，
+// 失败的行回滚到保存点后继续处理其余行
+func (r *movieRepository) CreateBatch(movies []*models.Movie, atomic bool) ([]error, error) {
+	rowErrors := make([]error, len(movies))
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i, movie := range movies {
+		if atomic {
+			if err := insertMovie(tx, movie); err != nil {
+				rowErrors[i] = err
+				return rowErrors, nil
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+		if err := insertMovie(tx, movie); err != nil {
+			rowErrors[i] = err
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT import_row"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rowErrors, nil
+}
+
+// ExistingTitles 返回给定标题中已存在于movies表中的标题集合
+func (r *movieRepository) ExistingTitles(titles []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(titles) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.db.Query(`SELECT title FROM movies WHERE title = ANY($1)`, pq.Array(titles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, err
+		}
+		existing[title] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// insertMovie 在事务中插入电影及其类型关联、内容分级
+func insertMovie(tx *sql.Tx, movie *models.Movie) error {
 	query := `
-		INSERT INTO movies (id, title, release_date, genre, distributor, budget, mpa_rating, box_office)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO movies (id, title, release_date, genre, distributor, budget, box_office)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
-	err := r.db.QueryRow(query, movie.ID, movie.Title, movie.ReleaseDate, movie.Genre,
-		movie.Distributor, movie.Budget, movie.MPARating, movie.BoxOffice).Scan(&movie.ID)
+	err := tx.QueryRow(query, movie.ID, movie.Title, movie.ReleaseDate, movie.Genre,
+		movie.Distributor, movie.Budget, movie.BoxOffice).Scan(&movie.ID)
+	if err != nil {
+		return err
+	}
+
+	// movie.Genres中保存的是已校验的标准类型名称，按顺序写入
+	for position, genre := range movie.Genres {
+		_, err := tx.Exec(`
+			INSERT INTO movie_genres (movie_id, genre_id, position)
+			SELECT $1, id, $3 FROM genres WHERE name = $2
+			ON CONFLICT DO NOTHING
+		`, movie.ID, genre, position)
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	// movie.ContentRatings中保存的是已校验的标准分级
+	return insertContentRatings(tx, movie.ID, movie.ContentRatings)
 }
 
-// GetByTitle 根据标题获取电影
+// GetByTitle 根据标题获取电影，标题可以是正式标题或任一别名（正式标题优先）
 func (r *movieRepository) GetByTitle(title string) (*models.Movie, error) {
 	query := `
-		SELECT id, title, release_date, genre, distributor, budget, mpa_rating, box_office
+		SELECT id, title, release_date, genre, distributor, budget, box_office,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = movies.id ORDER BY mg.position) AS genres,
+		       ` + contentRatingsColumn("movies.id") + `
 		FROM movies
 		WHERE title = $1
+		   OR id IN (SELECT movie_id FROM movie_aliases WHERE title = $1)
+		ORDER BY (title = $1) DESC, release_date DESC
+		LIMIT 1
 	`
 
 	var movie models.Movie
 	var boxOfficeJSON sql.NullString
+	var contentRatingsJSON []byte
 
 	err := r.db.QueryRow(query, title).Scan(
 		&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.Genre,
-		&movie.Distributor, &movie.Budget, &movie.MPARating, &boxOfficeJSON,
+		&movie.Distributor, &movie.Budget, &boxOfficeJSON,
+		pq.Array(&movie.Genres), &contentRatingsJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -62,14 +170,12 @@ func (r *movieRepository) GetByTitle(title string) (*models.Movie, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := applyContentRatings(&movie, contentRatingsJSON); err != nil {
+		return nil, err
+	}
 
 	// 解析box_office JSON
-	if boxOfficeJSON.Valid && boxOfficeJSON.String != "" {
-		var boxOffice models.BoxOffice
-		if err := json.Unmarshal([]byte(boxOfficeJSON.String), &boxOffice); err == nil {
-			movie.BoxOffice = &boxOffice
-		}
-	}
+	movie.BoxOffice = parseBoxOffice(boxOfficeJSON)
 
 	return &movie, nil
 }
@@ -79,60 +185,31 @@ func (r *movieRepository) List(query map[string]interface{}, limit int, cursor s
 	if limit <= 0 {
 		limit = 10
 	}
+	offset := decodeOffsetCursor(cursor)
 
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	// 构建查询条件
-	if q, ok := query["q"].(string); ok && q != "" {
-		conditions = append(conditions, fmt.Sprintf("title ILIKE $%d", argIndex))
-		args = append(args, "%"+q+"%")
-		argIndex++
-	}
-
-	if year, ok := query["year"].(int); ok && year > 0 {
-		conditions = append(conditions, fmt.Sprintf("EXTRACT(YEAR FROM release_date) = $%d", argIndex))
-		args = append(args, year)
-		argIndex++
-	}
-
-	if genre, ok := query["genre"].(string); ok && genre != "" {
-		conditions = append(conditions, fmt.Sprintf("genre ILIKE $%d", argIndex))
-		args = append(args, genre)
-		argIndex++
-	}
-
-	if distributor, ok := query["distributor"].(string); ok && distributor != "" {
-		conditions = append(conditions, fmt.Sprintf("distributor ILIKE $%d", argIndex))
-		args = append(args, distributor)
-		argIndex++
+	orderBy, err := movieListOrder(query)
+	if err != nil {
+		return nil, err
 	}
 
-	if budget, ok := query["budget"].(int64); ok && budget > 0 {
-		conditions = append(conditions, fmt.Sprintf("budget <= $%d", argIndex))
-		args = append(args, budget)
-		argIndex++
-	}
+	conditions, args := buildMovieFilters(query)
+	argIndex := len(args) + 1
 
-	if mpaRating, ok := query["mpaRating"].(string); ok && mpaRating != "" {
-		conditions = append(conditions, fmt.Sprintf("mpa_rating = $%d", argIndex))
-		args = append(args, mpaRating)
-		argIndex++
-	}
-
-	// 构建SQL查询
-	sqlQuery := "SELECT id, title, release_date, genre, distributor, budget, mpa_rating, box_office FROM movies"
+	// 构建SQL查询，评分聚合读取物化表
+	sqlQuery := "SELECT id, title, release_date, genre, distributor, budget, box_office, " +
+		"ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = movies.id ORDER BY mg.position) AS genres, " +
+		contentRatingsColumn("movies.id") + ", " +
+		"COALESCE(stats.rating_avg, 0), COALESCE(stats.rating_count, 0) " +
+		"FROM movies LEFT JOIN movie_rating_stats stats ON stats.movie_title = movies.title"
 	if len(conditions) > 0 {
 		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	sqlQuery += " ORDER BY release_date DESC, title ASC"
+	sqlQuery += " ORDER BY " + orderBy
 
 	// 添加分页
-	sqlQuery += fmt.Sprintf(" LIMIT $%d", argIndex)
-	args = append(args, limit+1) // 获取多一行用于判断是否有下一页
-	argIndex++
+	sqlQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, limit+1, offset) // 获取多一行用于判断是否有下一页
 
 	// 执行查询
 	rows, err := r.db.Query(sqlQuery, args...)
@@ -146,22 +223,24 @@ func (r *movieRepository) List(query map[string]interface{}, limit int, cursor s
 	for rows.Next() {
 		var movie models.Movie
 		var boxOfficeJSON sql.NullString
+		var contentRatingsJSON []byte
+		var ratings models.RatingAggregate
 
 		err := rows.Scan(
 			&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.Genre,
-			&movie.Distributor, &movie.Budget, &movie.MPARating, &boxOfficeJSON,
+			&movie.Distributor, &movie.Budget, &boxOfficeJSON,
+			pq.Array(&movie.Genres), &contentRatingsJSON, &ratings.Average, &ratings.Count,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := applyContentRatings(&movie, contentRatingsJSON); err != nil {
+			return nil, err
+		}
 
 		// 解析box_office JSON
-		if boxOfficeJSON.Valid && boxOfficeJSON.String != "" {
-			var boxOffice models.BoxOffice
-			if err := json.Unmarshal([]byte(boxOfficeJSON.String), &boxOffice); err == nil {
-				movie.BoxOffice = &boxOffice
-			}
-		}
+		movie.BoxOffice = parseBoxOffice(boxOfficeJSON)
+		movie.Ratings = &ratings
 
 		movies = append(movies, movie)
 	}
@@ -172,22 +251,207 @@ func (r *movieRepository) List(query map[string]interface{}, limit int, cursor s
 	// 检查是否有下一页
 	if len(movies) > limit {
 		result.Items = movies[:limit]
-		// 这里简单实现，实际应该返回基于ID或排序字段的游标
-		nextCursor := "next"
+		nextCursor := encodeOffsetCursor(offset + limit)
 		result.NextCursor = &nextCursor
 	}
 
 	return result, nil
 }
 
-// Update 更新电影信息
+// movieListSorts 电影列表可用的排序方式及其默认方向
+var movieListSorts = map[string]struct {
+	columns      []string
+	defaultOrder string
+}{
+	"releaseDate": {[]string{"release_date"}, "desc"},
+	"title":       {[]string{"title"}, "asc"},
+	"rating":      {[]string{"COALESCE(stats.rating_avg, 0)", "COALESCE(stats.rating_count, 0)"}, "desc"},
+	"ratingCount": {[]string{"COALESCE(stats.rating_count, 0)", "COALESCE(stats.rating_avg, 0)"}, "desc"},
+}
+
+// movieListOrder 根据sort和order参数生成ORDER BY子句，默认按上映日期倒序
+func movieListOrder(query map[string]interface{}) (string, error) {
+	sortBy, _ := query["sort"].(string)
+	if sortBy == "" {
+		sortBy = "releaseDate"
+	}
+	sort, ok := movieListSorts[sortBy]
+	if !ok {
+		return "", fmt.Errorf("invalid sort field: %s", sortBy)
+	}
+
+	order, _ := query["order"].(string)
+	if order == "" {
+		order = sort.defaultOrder
+	}
+	if order != "asc" && order != "desc" {
+		return "", fmt.Errorf("invalid sort order: %s", order)
+	}
+
+	var parts []string
+	for _, column := range sort.columns {
+		parts = append(parts, column+" "+strings.ToUpper(order))
+	}
+	// 以标题作为最终排序键，保证分页稳定
+	if sortBy != "title" {
+		parts = append(parts, "title ASC")
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// ValidateMovieListOrder 校验sort和order参数，供需要在输出前发现参数错误的流式导出使用
+func ValidateMovieListOrder(query map[string]interface{}) error {
+	_, err := movieListOrder(query)
+	return err
+}
+
+// buildMovieFilters 根据查询参数构建电影过滤条件，List与导出共用同一套过滤规则
+func buildMovieFilters(query map[string]interface{}) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	// 构建查询条件
+	if q, ok := query["q"].(string); ok && q != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"(title ILIKE $%[1]d OR EXISTS (SELECT 1 FROM movie_aliases a WHERE a.movie_id = movies.id AND a.title ILIKE $%[1]d))",
+			argIndex))
+		args = append(args, "%"+q+"%")
+		argIndex++
+	}
+
+	// 最少评分数过滤（读取物化聚合）
+	if minRatings, ok := query["minRatings"].(int); ok && minRatings > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"COALESCE((SELECT s.rating_count FROM movie_rating_stats s WHERE s.movie_title = movies.title), 0) >= $%d", argIndex))
+		args = append(args, minRatings)
+		argIndex++
+	}
+
+	// 指定地区时按该地区最早的上映日期（任意渠道）计算年份，没有该地区上映记录的电影使用首映日期
+	if year, ok := query["year"].(int); ok && year > 0 {
+		if region, ok := query["region"].(string); ok && region != "" {
+			conditions = append(conditions, fmt.Sprintf(`EXTRACT(YEAR FROM COALESCE(
+				(SELECT MIN(mr.release_date) FROM movie_releases mr WHERE mr.movie_id = movies.id AND mr.region = $%d),
+				release_date)) = $%d`, argIndex, argIndex+1))
+			args = append(args, region, year)
+			argIndex += 2
+		} else {
+			conditions = append(conditions, fmt.Sprintf("EXTRACT(YEAR FROM release_date) = $%d", argIndex))
+			args = append(args, year)
+			argIndex++
+		}
+	}
+
+	// 类型通过分类表匹配，同义词（如 "sci fi"）也能命中标准类型
+	if genre, ok := query["genre"].(string); ok && genre != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+			WHERE mg.movie_id = movies.id AND (g.key = $%[1]d OR g.id IN (SELECT genre_id FROM genre_synonyms WHERE key = $%[1]d)))`,
+			argIndex))
+		args = append(args, models.GenreKey(genre))
+		argIndex++
+	}
+
+	// 用户标签按归一化键匹配，"slow burn"与"Slow-Burn"命中同一标签
+	if tag, ok := query["tag"].(string); ok && tag != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (SELECT 1 FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id
+			WHERE mt.movie_id = movies.id AND t.key = $%d)`, argIndex))
+		args = append(args, models.TagKey(tag))
+		argIndex++
+	}
+
+	if distributor, ok := query["distributor"].(string); ok && distributor != "" {
+		conditions = append(conditions, fmt.Sprintf("distributor ILIKE $%d", argIndex))
+		args = append(args, distributor)
+		argIndex++
+	}
+
+	if budget, ok := query["budget"].(int64); ok && budget > 0 {
+		conditions = append(conditions, fmt.Sprintf("budget <= $%d", argIndex))
+		args = append(args, budget)
+		argIndex++
+	}
+
+	// mpaRating为MPA分级的兼容过滤
+	if mpaRating, ok := query["mpaRating"].(string); ok && mpaRating != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (SELECT 1 FROM movie_content_ratings cr
+			WHERE cr.movie_id = movies.id AND cr.system = 'MPA' AND UPPER(cr.rating) = UPPER($%d))`, argIndex))
+		args = append(args, mpaRating)
+		argIndex++
+	}
+
+	// 适合N岁观看：指定分级制度时按该制度的级别判断；否则取电影所有分级中最严格的一个，
+	// 没有任何分级的电影视为未知，不计入结果
+	if age, ok := query["suitableForAge"].(int); ok && age >= 0 {
+		if system, ok := query["contentRatingSystem"].(string); ok && system != "" {
+			conditions = append(conditions, fmt.Sprintf(`EXISTS (SELECT 1 FROM movie_content_ratings cr
+				JOIN content_rating_levels l ON l.system = cr.system AND l.code = cr.rating
+				WHERE cr.movie_id = movies.id AND cr.system = $%d AND l.min_age <= $%d)`, argIndex, argIndex+1))
+			args = append(args, system, age)
+			argIndex += 2
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`(SELECT MAX(l.min_age) FROM movie_content_ratings cr
+				JOIN content_rating_levels l ON l.system = cr.system AND l.code = cr.rating
+				WHERE cr.movie_id = movies.id) <= $%d`, argIndex))
+			args = append(args, age)
+			argIndex++
+		}
+	}
+
+	// 按演职人员过滤，可选限定角色
+	if person, ok := query["person"].(string); ok && person != "" {
+		creditCondition := fmt.Sprintf("mc.person_id = $%d", argIndex)
+		args = append(args, person)
+		argIndex++
+
+		if role, ok := query["role"].(string); ok && role != "" {
+			creditCondition += fmt.Sprintf(" AND mc.role = $%d", argIndex)
+			args = append(args, role)
+			argIndex++
+		}
+
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM movie_credits mc WHERE mc.movie_id = movies.id AND "+creditCondition+")")
+	}
+
+	return conditions, args
+}
+
+// Update 更新电影信息，并以movie.ContentRatings替换电影的内容分级
 func (r *movieRepository) Update(movie *models.Movie) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE movies
-		SET distributor = $1, budget = $2, mpa_rating = $3, box_office = $4
-		WHERE title = $5
+		SET distributor = $1, budget = $2, box_office = $3
+		WHERE id = $4
 	`
+	if _, err := tx.Exec(query, movie.Distributor, movie.Budget, movie.BoxOffice, movie.ID); err != nil {
+		return err
+	}
 
-	_, err := r.db.Exec(query, movie.Distributor, movie.Budget, movie.MPARating, movie.BoxOffice, movie.Title)
-	return err
+	if _, err := tx.Exec(`DELETE FROM movie_content_ratings WHERE movie_id = $1`, movie.ID); err != nil {
+		return err
+	}
+	if err := insertContentRatings(tx, movie.ID, movie.ContentRatings); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// parseBoxOffice 解析数据库中的box_office JSON，为空或无法解析时返回nil
+func parseBoxOffice(boxOfficeJSON sql.NullString) *models.BoxOffice {
+	if !boxOfficeJSON.Valid || boxOfficeJSON.String == "" {
+		return nil
+	}
+
+	var boxOffice models.BoxOffice
+	if err := json.Unmarshal([]byte(boxOfficeJSON.String), &boxOffice); err != nil {
+		return nil
+	}
+	return &boxOffice
 }
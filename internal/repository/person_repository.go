@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"movie-rating-api/internal/models"
+)
+
+// PersonRepository 演职人员存储库接口
+type PersonRepository interface {
+	Create(person *models.Person) error
+	GetByID(id string) (*models.Person, error)
+	List(q string, limit int, cursor string) (*models.PersonPage, error)
+	Update(person *models.Person) error
+	Delete(id string) (bool, error)
+}
+
+// personRepository 演职人员存储库实现
+type personRepository struct {
+	db *sql.DB
+}
+
+// NewPersonRepository 创建演职人员存储库实例
+func NewPersonRepository(db *sql.DB) PersonRepository {
+	return &personRepository{db: db}
+}
+
+// Create 创建演职人员
+func (r *personRepository) Create(person *models.Person) error {
+	query := `
+		INSERT INTO people (id, name, birth_date, biography)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(query, person.ID, person.Name, person.BirthDate, person.Biography).Scan(&person.ID)
+}
+
+// GetByID 根据ID获取演职人员
+func (r *personRepository) GetByID(id string) (*models.Person, error) {
+	query := `
+		SELECT id, name, TO_CHAR(birth_date, 'YYYY-MM-DD'), biography
+		FROM people
+		WHERE id = $1
+	`
+
+	var person models.Person
+	err := r.db.QueryRow(query, id).Scan(&person.ID, &person.Name, &person.BirthDate, &person.Biography)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &person, nil
+}
+
+// List 列出演职人员，支持按姓名搜索和分页
+func (r *personRepository) List(q string, limit int, cursor string) (*models.PersonPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	sqlQuery := `
+		SELECT id, name, TO_CHAR(birth_date, 'YYYY-MM-DD'), biography
+		FROM people
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%')
+		ORDER BY name ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(sqlQuery, q, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := []models.Person{}
+	for rows.Next() {
+		var person models.Person
+		if err := rows.Scan(&person.ID, &person.Name, &person.BirthDate, &person.Biography); err != nil {
+			return nil, err
+		}
+		people = append(people, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.PersonPage{Items: people}
+	if len(people) > limit {
+		result.Items = people[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// Update 更新演职人员信息
+func (r *personRepository) Update(person *models.Person) error {
+	query := `
+		UPDATE people
+		SET name = $1, birth_date = $2, biography = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`
+
+	res, err := r.db.Exec(query, person.Name, person.BirthDate, person.Biography, person.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("person not found")
+	}
+	return nil
+}
+
+// Delete 删除演职人员（署名随外键级联删除），返回是否有记录被删除
+func (r *personRepository) Delete(id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM people WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+	"time"
+)
+
+// RateLimitRepository 基于PostgreSQL的限流令牌桶存储，供多副本共享
+type RateLimitRepository interface {
+	Take(key string, limit models.RateLimit) (*models.RateLimitResult, error)
+	Peek(key string, limit models.RateLimit) (*models.RateLimitResult, error)
+	DeleteIdle(olderThan time.Duration) (int64, error)
+}
+
+// rateLimitRepository 限流令牌桶存储实现
+type rateLimitRepository struct {
+	db *sql.DB
+}
+
+// NewRateLimitRepository 创建限流令牌桶存储实例
+func NewRateLimitRepository(db *sql.DB) RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+// Take 从令牌桶中取一个令牌。行锁保证多副本并发时计数正确，
+// 经过时间使用数据库时钟计算，避免各副本时钟不一致
+func (r *rateLimitRepository) Take(key string, limit models.RateLimit) (*models.RateLimitResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 首次访问时创建满桶
+	_, err = tx.Exec(`
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+		VALUES ($1, $2, clock_timestamp())
+		ON CONFLICT (key) DO NOTHING
+	`, key, float64(limit.Requests))
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens, elapsedSeconds float64
+	err = tx.QueryRow(`
+		SELECT tokens, GREATEST(EXTRACT(EPOCH FROM (clock_timestamp() - updated_at)), 0)
+		FROM rate_limit_buckets
+		WHERE key = $1
+		FOR UPDATE
+	`, key).Scan(&tokens, &elapsedSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, result := limit.Take(tokens, time.Duration(elapsedSeconds*float64(time.Second)))
+
+	_, err = tx.Exec(`UPDATE rate_limit_buckets SET tokens = $2, updated_at = clock_timestamp() WHERE key = $1`, key, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Peek 查看令牌桶中是否还有令牌，不取令牌；桶不存在时视为满桶
+func (r *rateLimitRepository) Peek(key string, limit models.RateLimit) (*models.RateLimitResult, error) {
+	var tokens, elapsedSeconds float64
+	err := r.db.QueryRow(`
+		SELECT tokens, GREATEST(EXTRACT(EPOCH FROM (clock_timestamp() - updated_at)), 0)
+		FROM rate_limit_buckets
+		WHERE key = $1
+	`, key).Scan(&tokens, &elapsedSeconds)
+	if err == sql.ErrNoRows {
+		return &models.RateLimitResult{Allowed: true, Limit: limit.Requests, Remaining: limit.Requests}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, result := limit.Take(tokens, time.Duration(elapsedSeconds*float64(time.Second)))
+	return &result, nil
+}
+
+// DeleteIdle 删除长时间未使用的令牌桶（这些桶早已补满，删除后等价于满桶）
+func (r *rateLimitRepository) DeleteIdle(olderThan time.Duration) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM rate_limit_buckets WHERE updated_at < clock_timestamp() - make_interval(secs => $1)`, olderThan.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+	"time"
+)
+
+// RatingEventRepository 评分事件存储库接口（事件由评分存储库在写评分时追加）
+type RatingEventRepository interface {
+	ListByMovie(movieTitle string, limit int, cursor string) (*models.RatingEventPage, error)
+	GetAggregateAsOf(movieTitle string, asOf time.Time) (*models.RatingAggregate, error)
+}
+
+// ratingEventRepository 评分事件存储库实现
+type ratingEventRepository struct {
+	db *sql.DB
+}
+
+// NewRatingEventRepository 创建评分事件存储库实例
+func NewRatingEventRepository(db *sql.DB) RatingEventRepository {
+	return &ratingEventRepository{db: db}
+}
+
+// insertRatingEvent 在事务中追加一条评分事件
+func insertRatingEvent(tx *sql.Tx, movieTitle, raterID, eventType string, oldRating, newRating *float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO rating_events (movie_title, rater_id, event_type, old_rating, new_rating)
+		VALUES ($1, $2, $3, $4, $5)
+	`, movieTitle, raterID, eventType, oldRating, newRating)
+	return err
+}
+
+// ListByMovie 按时间顺序分页列出电影的评分事件
+func (r *ratingEventRepository) ListByMovie(movieTitle string, limit int, cursor string) (*models.RatingEventPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT id, movie_title, rater_id, event_type, old_rating, new_rating, occurred_at
+		FROM rating_events
+		WHERE movie_title = $1
+		ORDER BY occurred_at ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, movieTitle, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.RatingEvent{}
+	for rows.Next() {
+		var event models.RatingEvent
+		err := rows.Scan(&event.ID, &event.MovieTitle, &event.RaterID, &event.EventType,
+			&event.OldRating, &event.NewRating, &event.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.RatingEventPage{Items: events}
+	if len(events) > limit {
+		result.Items = events[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// GetAggregateAsOf 根据事件重放计算电影在asOf时刻的聚合评分：
+// 取每个评分者在该时刻之前的最后一个事件，未被删除的计入
+func (r *ratingEventRepository) GetAggregateAsOf(movieTitle string, asOf time.Time) (*models.RatingAggregate, error) {
+	query := `
+		SELECT COALESCE(AVG(new_rating), 0), COUNT(*)
+		FROM (
+			SELECT DISTINCT ON (rater_id) rater_id, event_type, new_rating
+			FROM rating_events
+			WHERE movie_title = $1 AND occurred_at <= $2
+			ORDER BY rater_id, occurred_at DESC, id DESC
+		) latest
+		WHERE event_type <> 'deleted'
+	`
+
+	// occurred_at带时区，asOf显式转为UTC后比较，结果与数据库和会话时区无关
+	asOf = asOf.UTC()
+	aggregate := &models.RatingAggregate{AsOf: &asOf}
+	if err := r.db.QueryRow(query, movieTitle, asOf).Scan(&aggregate.Average, &aggregate.Count); err != nil {
+		return nil, err
+	}
+
+	return aggregate, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+	"time"
+)
+
+// RatingFlagRepository 可疑评分标记存储库接口
+type RatingFlagRepository interface {
+	RecentActivity(movieTitle string, window time.Duration) ([]models.RatingActivity, error)
+	CountSubmissions(movieTitle string, from, to time.Duration) (int, error)
+	CreateFlags(flags []models.RatingFlag) (int, error)
+	List(status string, limit int, cursor string) (*models.RatingFlagPage, error)
+	Review(id int64, status, reviewer string) (*models.RatingFlag, error)
+}
+
+// ratingFlagRepository 可疑评分标记存储库实现
+type ratingFlagRepository struct {
+	db *sql.DB
+}
+
+// NewRatingFlagRepository 创建可疑评分标记存储库实例
+func NewRatingFlagRepository(db *sql.DB) RatingFlagRepository {
+	return &ratingFlagRepository{db: db}
+}
+
+// RecentActivity 获取电影在最近window内提交（或更新）的评分，以及每个评分者的首次评分时间。
+// 时间窗口以数据库时钟计算
+func (r *ratingFlagRepository) RecentActivity(movieTitle string, window time.Duration) ([]models.RatingActivity, error) {
+	query := `
+		SELECT r.rater_id, r.rating, r.updated_at,
+		       (SELECT MIN(r2.created_at) FROM ratings r2 WHERE r2.rater_id = r.rater_id)
+		FROM ratings r
+		WHERE r.movie_title = $1 AND r.updated_at >= CURRENT_TIMESTAMP - make_interval(secs => $2)
+		ORDER BY r.updated_at ASC
+	`
+
+	rows, err := r.db.Query(query, movieTitle, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := []models.RatingActivity{}
+	for rows.Next() {
+		var a models.RatingActivity
+		if err := rows.Scan(&a.RaterID, &a.Rating, &a.SubmittedAt, &a.RaterFirstSeen); err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// CountSubmissions 统计电影在[now-from, now-to)区间内最后一次提交的评分数，用作基线
+func (r *ratingFlagRepository) CountSubmissions(movieTitle string, from, to time.Duration) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM ratings
+		WHERE movie_title = $1
+		  AND updated_at >= CURRENT_TIMESTAMP - make_interval(secs => $2)
+		  AND updated_at < CURRENT_TIMESTAMP - make_interval(secs => $3)
+	`
+
+	var count int
+	err := r.db.QueryRow(query, movieTitle, from.Seconds(), to.Seconds()).Scan(&count)
+	return count, err
+}
+
+// CreateFlags 批量写入标记，已因同一原因标记过的评分会被跳过。返回新增的标记数
+func (r *ratingFlagRepository) CreateFlags(flags []models.RatingFlag) (int, error) {
+	if len(flags) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO rating_flags (movie_title, rater_id, reason, details)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (movie_title, rater_id, reason) DO NOTHING
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	created := 0
+	for _, flag := range flags {
+		res, err := stmt.Exec(flag.MovieTitle, flag.RaterID, flag.Reason, flag.Details)
+		if err != nil {
+			return 0, err
+		}
+		if affected, err := res.RowsAffected(); err == nil {
+			created += int(affected)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// ratingFlagColumns 查询标记时使用的列（rating_flags别名f，ratings别名r），评分已撤回时分数为NULL
+const ratingFlagColumns = "f.id, f.movie_title, f.rater_id, r.rating, f.reason, f.details, f.status, f.reviewed_by, f.reviewed_at, f.created_at"
+
+// scanRatingFlag 扫描一行标记记录
+func scanRatingFlag(scanner interface{ Scan(...interface{}) error }) (*models.RatingFlag, error) {
+	var flag models.RatingFlag
+	err := scanner.Scan(&flag.ID, &flag.MovieTitle, &flag.RaterID, &flag.Rating, &flag.Reason, &flag.Details,
+		&flag.Status, &flag.ReviewedBy, &flag.ReviewedAt, &flag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// List 按状态分页列出标记，最早的排在前面；status为空时列出全部
+func (r *ratingFlagRepository) List(status string, limit int, cursor string) (*models.RatingFlagPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT ` + ratingFlagColumns + `
+		FROM rating_flags f
+		LEFT JOIN ratings r ON r.movie_title = f.movie_title AND r.rater_id = f.rater_id
+		WHERE ($1 = '' OR f.status = $1)
+		ORDER BY f.created_at ASC, f.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, status, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []models.RatingFlag{}
+	for rows.Next() {
+		flag, err := scanRatingFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, *flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.RatingFlagPage{Items: flags}
+	if len(flags) > limit {
+		result.Items = flags[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// Review 更新标记的审核状态，标记不存在时返回nil
+func (r *ratingFlagRepository) Review(id int64, status, reviewer string) (*models.RatingFlag, error) {
+	query := `
+		WITH updated AS (
+			UPDATE rating_flags
+			SET status = $2, reviewed_by = NULLIF($3, ''), reviewed_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + ratingFlagColumns + `
+		FROM updated f
+		LEFT JOIN ratings r ON r.movie_title = f.movie_title AND r.rater_id = f.rater_id
+	`
+
+	flag, err := scanRatingFlag(r.db.QueryRow(query, id, status, reviewer))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return flag, err
+}
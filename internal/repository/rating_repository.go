@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"movie-rating-api/internal/models"
 )
 
@@ -9,7 +10,10 @@ import (
 type RatingRepository interface {
 	Upsert(rating *models.Rating) error
 	GetByMovieAndRater(movieTitle, raterID string) (*models.Rating, error)
-	GetAggregateByMovie(movieTitle string) (*models.RatingAggregate, error)
+	GetAggregateByMovie(movieTitle string, excludeFlagged bool) (*models.RatingAggregate, error)
+	ListByRater(raterID, sortBy, order string, limit int, cursor string) (*models.RaterRatingPage, error)
+	GetRaterStats(raterID string) (*models.RaterStats, error)
+	Delete(movieTitle, raterID string) (bool, error)
 }
 
 // ratingRepository 评分存储库实现
@@ -22,19 +26,60 @@ func NewRatingRepository(db *sql.DB) RatingRepository {
 	return &ratingRepository{db: db}
 }
 
-// Upsert 插入或更新评分
+// Upsert 插入或更新评分，并在同一事务中追加评分事件、更新物化聚合
 func (r *ratingRepository) Upsert(rating *models.Rating) error {
-	// 由于我们已将数据库字段改为FLOAT类型，可以直接使用float64值
-	query := `
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 先尝试插入；已有评分时锁定该行读取旧分数再更新，保证事件中的旧值准确
+	var inserted bool
+	err = tx.QueryRow(`
 		INSERT INTO ratings (movie_title, rater_id, rating, updated_at)
 		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
-		ON CONFLICT (movie_title, rater_id)
-		DO UPDATE SET rating = EXCLUDED.rating, updated_at = CURRENT_TIMESTAMP
-	`
+		ON CONFLICT (movie_title, rater_id) DO NOTHING
+		RETURNING true
+	`, rating.MovieTitle, rating.RaterID, rating.Rating).Scan(&inserted)
+
+	switch {
+	case err == nil:
+		if err := insertRatingEvent(tx, rating.MovieTitle, rating.RaterID, models.RatingEventCreated, nil, &rating.Rating); err != nil {
+			return err
+		}
+		if err := applyRatingStatsDelta(tx, rating.MovieTitle, 1, rating.Rating); err != nil {
+			return err
+		}
+	case err == sql.ErrNoRows:
+		var oldRating float64
+		err = tx.QueryRow(`
+			SELECT rating FROM ratings WHERE movie_title = $1 AND rater_id = $2 FOR UPDATE
+		`, rating.MovieTitle, rating.RaterID).Scan(&oldRating)
+		if err != nil {
+			return err
+		}
 
-	// 直接执行，无需特殊处理
-	_, err := r.db.Exec(query, rating.MovieTitle, rating.RaterID, rating.Rating)
-	return err
+		_, err = tx.Exec(`
+			UPDATE ratings SET rating = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE movie_title = $1 AND rater_id = $2
+		`, rating.MovieTitle, rating.RaterID, rating.Rating)
+		if err != nil {
+			return err
+		}
+
+		if err := insertRatingEvent(tx, rating.MovieTitle, rating.RaterID, models.RatingEventUpdated, &oldRating, &rating.Rating); err != nil {
+			return err
+		}
+		// 改分只调整总分，评分数不变
+		if err := applyRatingStatsDelta(tx, rating.MovieTitle, 0, rating.Rating-oldRating); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetByMovieAndRater 根据电影标题和评分者ID获取评分
@@ -60,28 +105,164 @@ func (r *ratingRepository) GetByMovieAndRater(movieTitle, raterID string) (*mode
 	return &rating, nil
 }
 
-// GetAggregateByMovie 获取电影的聚合评分
-func (r *ratingRepository) GetAggregateByMovie(movieTitle string) (*models.RatingAggregate, error) {
-	// 确保查询语句与FLOAT类型兼容
+// GetAggregateByMovie 获取电影的聚合评分。已确认为刷分的评分始终不计入；
+// excludeFlagged为true时待审核的可疑评分也不计入。读取物化聚合后减去需要排除的
+// 评分，只按主键查找被标记的评分，不扫描电影的全部评分
+func (r *ratingRepository) GetAggregateByMovie(movieTitle string, excludeFlagged bool) (*models.RatingAggregate, error) {
 	query := `
-		SELECT COALESCE(AVG(rating), 0) as average, COUNT(*) as count
-		FROM ratings
-		WHERE movie_title = $1
+		SELECT CASE WHEN COALESCE(s.rating_count, 0) - e.excluded_count > 0
+		            THEN (COALESCE(s.rating_sum, 0) - e.excluded_sum) / (COALESCE(s.rating_count, 0) - e.excluded_count)
+		            ELSE 0 END,
+		       GREATEST(COALESCE(s.rating_count, 0) - e.excluded_count, 0)
+		FROM (
+			SELECT COUNT(*) AS excluded_count, COALESCE(SUM(r.rating), 0) AS excluded_sum
+			FROM (
+				SELECT DISTINCT f.rater_id
+				FROM rating_flags f
+				WHERE f.movie_title = $1
+				  AND (f.status = 'confirmed' OR ($2 AND f.status = 'pending'))
+			) flagged
+			JOIN ratings r ON r.movie_title = $1 AND r.rater_id = flagged.rater_id
+		) e
+		LEFT JOIN movie_rating_stats s ON s.movie_title = $1
 	`
 
 	var aggregate models.RatingAggregate
-	var avg float64
-	var count int
+	if err := r.db.QueryRow(query, movieTitle, excludeFlagged).Scan(&aggregate.Average, &aggregate.Count); err != nil {
+		return nil, err
+	}
+
+	return &aggregate, nil
+}
+
+// raterRatingSortColumns 评分历史可用的排序字段
+var raterRatingSortColumns = map[string]string{
+	"date":  "updated_at",
+	"score": "rating",
+}
+
+// ListByRater 分页列出评分者的评分，sortBy为date或score，order为asc或desc
+func (r *ratingRepository) ListByRater(raterID, sortBy, order string, limit int, cursor string) (*models.RaterRatingPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	column, ok := raterRatingSortColumns[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort field: %s", sortBy)
+	}
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("invalid sort order: %s", order)
+	}
+
+	// 排序字段来自白名单，可以安全拼接
+	query := fmt.Sprintf(`
+		SELECT movie_title, rating, created_at, updated_at
+		FROM ratings
+		WHERE rater_id = $1
+		ORDER BY %s %s, movie_title ASC
+		LIMIT $2 OFFSET $3
+	`, column, order)
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, raterID, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	// 使用独立变量来扫描结果，确保类型兼容性
-	err := r.db.QueryRow(query, movieTitle).Scan(&avg, &count)
+	ratings := []models.RaterRating{}
+	for rows.Next() {
+		var rating models.RaterRating
+		if err := rows.Scan(&rating.MovieTitle, &rating.Rating, &rating.CreatedAt, &rating.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.RaterRatingPage{Items: ratings}
+	if len(ratings) > limit {
+		result.Items = ratings[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// GetRaterStats 获取评分者的评分数、平均分及按类型的分布（一部电影属于多个类型时分别计入）
+func (r *ratingRepository) GetRaterStats(raterID string) (*models.RaterStats, error) {
+	stats := &models.RaterStats{RaterID: raterID, Genres: []models.RaterGenreStats{}}
+
+	err := r.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(AVG(rating), 0)
+		FROM ratings
+		WHERE rater_id = $1
+	`, raterID).Scan(&stats.Count, &stats.Average)
 	if err != nil {
 		return nil, err
 	}
+	if stats.Count == 0 {
+		return stats, nil
+	}
 
-	// 手动赋值给结构体
-	aggregate.Average = avg
-	aggregate.Count = count
+	query := `
+		SELECT g.name, COUNT(*), AVG(r.rating)
+		FROM ratings r
+		JOIN movies m ON m.title = r.movie_title
+		JOIN movie_genres mg ON mg.movie_id = m.id
+		JOIN genres g ON g.id = mg.genre_id
+		WHERE r.rater_id = $1
+		GROUP BY g.name
+		ORDER BY COUNT(*) DESC, g.name ASC
+	`
 
-	return &aggregate, nil
+	rows, err := r.db.Query(query, raterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var genre models.RaterGenreStats
+		if err := rows.Scan(&genre.Genre, &genre.Count, &genre.Average); err != nil {
+			return nil, err
+		}
+		stats.Genres = append(stats.Genres, genre)
+	}
+
+	return stats, rows.Err()
+}
+
+// Delete 删除评分并追加删除事件、更新物化聚合，返回是否有记录被删除
+func (r *ratingRepository) Delete(movieTitle, raterID string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var oldRating float64
+	err = tx.QueryRow(`
+		DELETE FROM ratings WHERE movie_title = $1 AND rater_id = $2 RETURNING rating
+	`, movieTitle, raterID).Scan(&oldRating)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := insertRatingEvent(tx, movieTitle, raterID, models.RatingEventDeleted, &oldRating, nil); err != nil {
+		return false, err
+	}
+	if err := applyRatingStatsDelta(tx, movieTitle, -1, -oldRating); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
 }
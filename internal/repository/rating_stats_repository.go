@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+)
+
+// RatingStatsRepository 物化评分聚合存储库接口（增量维护由评分存储库在写评分时完成）
+type RatingStatsRepository interface {
+	FindDrift() ([]models.RatingStatsDrift, error)
+	Rebuild(movieTitle string) error
+}
+
+// ratingStatsRepository 物化评分聚合存储库实现
+type ratingStatsRepository struct {
+	db *sql.DB
+}
+
+// NewRatingStatsRepository 创建物化评分聚合存储库实例
+func NewRatingStatsRepository(db *sql.DB) RatingStatsRepository {
+	return &ratingStatsRepository{db: db}
+}
+
+// applyRatingStatsDelta 在事务中增量更新电影的评分数和总分
+func applyRatingStatsDelta(tx *sql.Tx, movieTitle string, countDelta int, sumDelta float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO movie_rating_stats (movie_title, rating_count, rating_sum, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (movie_title) DO UPDATE SET
+			rating_count = movie_rating_stats.rating_count + EXCLUDED.rating_count,
+			rating_sum = movie_rating_stats.rating_sum + EXCLUDED.rating_sum,
+			updated_at = CURRENT_TIMESTAMP
+	`, movieTitle, countDelta, sumDelta)
+	return err
+}
+
+// FindDrift 从评分表重新计算聚合，返回与物化表不一致的电影
+func (r *ratingStatsRepository) FindDrift() ([]models.RatingStatsDrift, error) {
+	query := `
+		SELECT COALESCE(a.movie_title, s.movie_title),
+		       COALESCE(s.rating_count, 0), COALESCE(s.rating_sum, 0),
+		       COALESCE(a.rating_count, 0), COALESCE(a.rating_sum, 0)
+		FROM (
+			SELECT movie_title, COUNT(*) AS rating_count, SUM(rating) AS rating_sum
+			FROM ratings
+			GROUP BY movie_title
+		) a
+		FULL OUTER JOIN movie_rating_stats s ON s.movie_title = a.movie_title
+		WHERE COALESCE(s.rating_count, 0) <> COALESCE(a.rating_count, 0)
+		   OR ABS(COALESCE(s.rating_sum, 0) - COALESCE(a.rating_sum, 0)) > 1e-6
+		ORDER BY 1
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drifts := []models.RatingStatsDrift{}
+	for rows.Next() {
+		var d models.RatingStatsDrift
+		if err := rows.Scan(&d.MovieTitle, &d.StoredCount, &d.StoredSum, &d.ActualCount, &d.ActualSum); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d)
+	}
+
+	return drifts, rows.Err()
+}
+
+// Rebuild 从评分表重新计算单部电影的聚合并覆盖物化表。先锁住物化行再统计，
+// 并发提交的评分要么在统计前提交、要么等重算提交后再增量更新，不会被覆盖丢失
+func (r *ratingStatsRepository) Rebuild(movieTitle string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO movie_rating_stats (movie_title, rating_count, rating_sum, updated_at)
+		VALUES ($1, 0, 0, CURRENT_TIMESTAMP)
+		ON CONFLICT (movie_title) DO NOTHING
+	`, movieTitle)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`SELECT 1 FROM movie_rating_stats WHERE movie_title = $1 FOR UPDATE`, movieTitle); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE movie_rating_stats s
+		SET rating_count = a.rating_count, rating_sum = a.rating_sum, updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT COUNT(*) AS rating_count, COALESCE(SUM(rating), 0) AS rating_sum
+			FROM ratings
+			WHERE movie_title = $1
+		) a
+		WHERE s.movie_title = $1
+	`, movieTitle)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"movie-rating-api/internal/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// RecommendationRepository 个性化推荐存储库接口
+type RecommendationRepository interface {
+	ListRaterRatings(raterID string) ([]models.Rating, error)
+	ListCandidates(raterID string, query map[string]interface{}) ([]models.RecommendationCandidate, error)
+}
+
+// recommendationRepository 个性化推荐存储库实现
+type recommendationRepository struct {
+	db *sql.DB
+}
+
+// NewRecommendationRepository 创建个性化推荐存储库实例
+func NewRecommendationRepository(db *sql.DB) RecommendationRepository {
+	return &recommendationRepository{db: db}
+}
+
+// ListRaterRatings 获取评分者当前的全部评分
+func (r *recommendationRepository) ListRaterRatings(raterID string) ([]models.Rating, error) {
+	rows, err := r.db.Query(`SELECT movie_title, rater_id, rating FROM ratings WHERE rater_id = $1`, raterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []models.Rating{}
+	for rows.Next() {
+		var rating models.Rating
+		if err := rows.Scan(&rating.MovieTitle, &rating.RaterID, &rating.Rating); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+// ListCandidates 获取评分者尚未评分、且满足电影列表过滤条件的电影
+func (r *recommendationRepository) ListCandidates(raterID string, query map[string]interface{}) ([]models.RecommendationCandidate, error) {
+	conditions, args := buildMovieFilters(query)
+	conditions = append(conditions, fmt.Sprintf(
+		"NOT EXISTS (SELECT 1 FROM ratings r WHERE r.movie_title = movies.title AND r.rater_id = $%d)", len(args)+1))
+	args = append(args, raterID)
+
+	sqlQuery := "SELECT title, release_date, " +
+		"ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = movies.id ORDER BY mg.position) " +
+		"FROM movies WHERE " + strings.Join(conditions, " AND ")
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []models.RecommendationCandidate{}
+	for rows.Next() {
+		var candidate models.RecommendationCandidate
+		if err := rows.Scan(&candidate.Title, &candidate.ReleaseDate, pq.Array(&candidate.Genres)); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"movie-rating-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ReleaseRepository 地区上映记录存储库接口
+type ReleaseRepository interface {
+	ListByMovie(movieID string) ([]models.MovieRelease, error)
+	Upsert(movieID string, release *models.MovieRelease) (bool, error)
+	Delete(movieID, region, channel string) (bool, error)
+	ListCalendar(from, to, region, channel string, limit int, cursor string) (*models.CalendarPage, error)
+}
+
+// releaseRepository 地区上映记录存储库实现
+type releaseRepository struct {
+	db *sql.DB
+}
+
+// NewReleaseRepository 创建地区上映记录存储库实例
+func NewReleaseRepository(db *sql.DB) ReleaseRepository {
+	return &releaseRepository{db: db}
+}
+
+// ListByMovie 按上映日期列出电影的全部上映记录
+func (r *releaseRepository) ListByMovie(movieID string) ([]models.MovieRelease, error) {
+	query := `
+		SELECT region, channel, TO_CHAR(release_date, 'YYYY-MM-DD'), note
+		FROM movie_releases
+		WHERE movie_id = $1
+		ORDER BY release_date ASC, region ASC, channel ASC
+	`
+
+	rows, err := r.db.Query(query, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	releases := []models.MovieRelease{}
+	for rows.Next() {
+		var release models.MovieRelease
+		if err := rows.Scan(&release.Region, &release.Channel, &release.ReleaseDate, &release.Note); err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, rows.Err()
+}
+
+// Upsert 设置电影在某地区某渠道的上映日期，返回是否新建了记录
+func (r *releaseRepository) Upsert(movieID string, release *models.MovieRelease) (bool, error) {
+	query := `
+		INSERT INTO movie_releases (movie_id, region, channel, release_date, note)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (movie_id, region, channel)
+		DO UPDATE SET release_date = EXCLUDED.release_date, note = EXCLUDED.note, updated_at = CURRENT_TIMESTAMP
+		RETURNING (xmax = 0)
+	`
+
+	var created bool
+	err := r.db.QueryRow(query, movieID, release.Region, release.Channel, release.ReleaseDate, release.Note).Scan(&created)
+	return created, err
+}
+
+// Delete 删除上映记录，返回是否有记录被删除
+func (r *releaseRepository) Delete(movieID, region, channel string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM movie_releases WHERE movie_id = $1 AND region = $2 AND channel = $3
+	`, movieID, region, channel)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListCalendar 分页列出[from, to]日期范围内的上映记录，region和channel为空时不过滤
+func (r *releaseRepository) ListCalendar(from, to, region, channel string, limit int, cursor string) (*models.CalendarPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	conditions := []string{"r.release_date BETWEEN $1 AND $2"}
+	args := []interface{}{from, to}
+	if region != "" {
+		args = append(args, region)
+		conditions = append(conditions, fmt.Sprintf("r.region = $%d", len(args)))
+	}
+	if channel != "" {
+		args = append(args, channel)
+		conditions = append(conditions, fmt.Sprintf("r.channel = $%d", len(args)))
+	}
+
+	// 条件只包含参数占位符，可以安全拼接
+	query := fmt.Sprintf(`
+		SELECT m.id, m.title,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = m.id ORDER BY mg.position),
+		       r.region, r.channel, TO_CHAR(r.release_date, 'YYYY-MM-DD')
+		FROM movie_releases r
+		JOIN movies m ON m.id = r.movie_id
+		WHERE %s
+		ORDER BY r.release_date ASC, m.title ASC, r.region ASC, r.channel ASC
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conditions, " AND "), len(args)+1, len(args)+2)
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, append(args, limit+1, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.CalendarEntry{}
+	for rows.Next() {
+		var entry models.CalendarEntry
+		err := rows.Scan(&entry.MovieID, &entry.Title, pq.Array(&entry.Genres),
+			&entry.Region, &entry.Channel, &entry.ReleaseDate)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.CalendarPage{From: from, To: to, Items: entries}
+	if len(entries) > limit {
+		result.Items = entries[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"movie-rating-api/internal/models"
+)
+
+// ReviewRepository 评论存储库接口
+type ReviewRepository interface {
+	Upsert(review *models.Review) (bool, bool, error)
+	GetByID(id int64, viewerID string) (*models.Review, error)
+	ListByMovie(movieTitle, sortBy, viewerID string, limit int, cursor string) (*models.ReviewPage, error)
+	Vote(reviewID int64, voterID string, helpful bool) (bool, error)
+	DeleteVote(reviewID int64, voterID string) (bool, error)
+	AddReaction(reviewID int64, raterID, reaction string) error
+	RemoveReaction(reviewID int64, raterID, reaction string) (bool, error)
+	SetStatus(reviewID int64, from, to, moderator, reason string) (bool, error)
+	ListQueue(status string, limit int, cursor string) (*models.ModerationQueuePage, error)
+	ListModerationEvents(reviewID int64) ([]models.ReviewModerationEvent, error)
+	CreateReport(report *models.ReviewReport) error
+	CountOpenReports(reviewID int64) (int, error)
+}
+
+// reviewRepository 评论存储库实现
+type reviewRepository struct {
+	db *sql.DB
+}
+
+// NewReviewRepository 创建评论存储库实例
+func NewReviewRepository(db *sql.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// reviewColumns 评论及评分、票数、回应和查看者投票的列，$1为查看者ID
+const reviewColumns = `
+	r.id, r.movie_title, r.rater_id, rt.rating, r.body, r.status, r.created_at, r.updated_at,
+	v.helpful, v.not_helpful,
+	COALESCE((SELECT json_object_agg(x.reaction, x.n) FROM (
+	    SELECT reaction, COUNT(*) AS n FROM review_reactions WHERE review_id = r.id GROUP BY reaction
+	) x), '{}'),
+	(SELECT helpful FROM review_votes WHERE review_id = r.id AND voter_id = $1)
+`
+
+// reviewFrom 评论查询的FROM子句，关联评分并统计票数
+const reviewFrom = `
+	FROM reviews r
+	JOIN ratings rt ON rt.movie_title = r.movie_title AND rt.rater_id = r.rater_id
+	CROSS JOIN LATERAL (
+	    SELECT COUNT(*) FILTER (WHERE helpful) AS helpful, COUNT(*) FILTER (WHERE NOT helpful) AS not_helpful
+	    FROM review_votes WHERE review_id = r.id
+	) v
+`
+
+// reviewSelect 查询评论的完整SELECT语句
+const reviewSelect = `SELECT ` + reviewColumns + reviewFrom
+
+// reviewSorts 评论排序方式对应的ORDER BY子句。helpful按有用率的Wilson置信下限排序，
+// 少量投票的评论不会因为一两张有用票排到大量好评的评论前面
+var reviewSorts = map[string]string{
+	models.ReviewSortRecent: "r.created_at DESC, r.id DESC",
+	models.ReviewSortHelpful: `CASE WHEN v.helpful + v.not_helpful = 0 THEN 0 ELSE
+		((v.helpful + 1.9208) / (v.helpful + v.not_helpful)
+		 - 1.96 * SQRT((v.helpful * v.not_helpful)::float / (v.helpful + v.not_helpful) + 0.9604) / (v.helpful + v.not_helpful))
+		/ (1 + 3.8416 / (v.helpful + v.not_helpful)) END DESC, v.helpful DESC, r.created_at DESC, r.id DESC`,
+}
+
+// scanReview 扫描一行评论数据，extra为reviewColumns之后的附加列
+func scanReview(scanner interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.Review, error) {
+	var review models.Review
+	var reactionsJSON []byte
+	var myVote sql.NullBool
+
+	dest := []interface{}{
+		&review.ID, &review.MovieTitle, &review.RaterID, &review.Rating, &review.Body, &review.Status, &review.CreatedAt, &review.UpdatedAt,
+		&review.Votes.Helpful, &review.Votes.NotHelpful, &reactionsJSON, &myVote,
+	}
+	err := scanner.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Reactions = map[string]int{}
+	if err := json.Unmarshal(reactionsJSON, &review.Reactions); err != nil {
+		return nil, err
+	}
+	if myVote.Valid {
+		review.MyVote = &myVote.Bool
+	}
+
+	return &review, nil
+}
+
+// Upsert 创建或更新评分者对电影的评论。新评论和内容有变化的评论进入pending状态等待审核，
+// 已审核过的评论被修改时记录一条重新审核事件。返回评论内容是否为新的（需要审核），
+// 以及是否只能人工审核：被隐藏或拒绝的评论修改后，直到版主再次决定前都只能人工审核
+func (r *reviewRepository) Upsert(review *models.Review) (bool, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO reviews (movie_title, rater_id, body, status)
+		VALUES ($1, $2, $3, 'pending')
+		ON CONFLICT (movie_title, rater_id) DO NOTHING
+		RETURNING id, status, created_at, updated_at
+	`, review.MovieTitle, review.RaterID, review.Body).Scan(&review.ID, &review.Status, &review.CreatedAt, &review.UpdatedAt)
+	if err == nil {
+		return true, false, tx.Commit()
+	}
+	if err != sql.ErrNoRows {
+		return false, false, err
+	}
+
+	// 已有评论：锁定后比较内容
+	var oldBody, oldStatus string
+	err = tx.QueryRow(`
+		SELECT id, body, status, created_at, updated_at FROM reviews
+		WHERE movie_title = $1 AND rater_id = $2
+		FOR UPDATE
+	`, review.MovieTitle, review.RaterID).Scan(&review.ID, &oldBody, &oldStatus, &review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		return false, false, err
+	}
+	if oldBody == review.Body {
+		review.Status = oldStatus
+		return false, false, tx.Commit()
+	}
+
+	err = tx.QueryRow(`
+		UPDATE reviews SET body = $2, status = 'pending', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING status, updated_at
+	`, review.ID, review.Body).Scan(&review.Status, &review.UpdatedAt)
+	if err != nil {
+		return false, false, err
+	}
+
+	manualOnly := oldStatus == models.ReviewStatusHidden || oldStatus == models.ReviewStatusRejected
+	if oldStatus != models.ReviewStatusPending {
+		if err := insertModerationEvent(tx, review.ID, oldStatus, models.ReviewStatusPending, "system", "review edited by author"); err != nil {
+			return false, false, err
+		}
+	} else {
+		// 仍在等待审核：最近一次状态变化是从隐藏或拒绝改回pending时，仍只能人工审核
+		err = tx.QueryRow(`
+			SELECT COALESCE((
+				SELECT from_status IN ('hidden', 'rejected')
+				FROM review_moderation_events
+				WHERE review_id = $1
+				ORDER BY id DESC
+				LIMIT 1
+			), false)
+		`, review.ID).Scan(&manualOnly)
+		if err != nil {
+			return false, false, err
+		}
+	}
+
+	return true, manualOnly, tx.Commit()
+}
+
+// GetByID 根据ID获取评论
+func (r *reviewRepository) GetByID(id int64, viewerID string) (*models.Review, error) {
+	review, err := scanReview(r.db.QueryRow(reviewSelect+` WHERE r.id = $2`, viewerID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return review, err
+}
+
+// ListByMovie 分页获取电影的评论，sortBy为recent或helpful
+func (r *reviewRepository) ListByMovie(movieTitle, sortBy, viewerID string, limit int, cursor string) (*models.ReviewPage, error) {
+	orderBy, ok := reviewSorts[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: must be 'recent' or 'helpful'")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	// 只公开已通过审核的评论，作者能看到自己尚未公开的评论
+	query := reviewSelect + ` WHERE r.movie_title = $2 AND (r.status = 'approved' OR r.rater_id = $1)
+		ORDER BY ` + orderBy + ` LIMIT $3 OFFSET $4`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, viewerID, movieTitle, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.ReviewPage{Items: reviews}
+	if len(reviews) > limit {
+		result.Items = reviews[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// Vote 记录或修改评分者对评论的投票。评论作者本人的投票和未公开评论的投票在同一条语句中被排除，
+// 返回是否记录了投票（评论不存在、未公开或为本人评论时为false）
+func (r *reviewRepository) Vote(reviewID int64, voterID string, helpful bool) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO review_votes (review_id, voter_id, helpful)
+		SELECT id, $2, $3 FROM reviews WHERE id = $1 AND rater_id <> $2 AND status = 'approved'
+		ON CONFLICT (review_id, voter_id)
+		DO UPDATE SET helpful = EXCLUDED.helpful, updated_at = CURRENT_TIMESTAMP
+	`, reviewID, voterID, helpful)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteVote 撤回评分者对评论的投票，返回是否有记录被删除
+func (r *reviewRepository) DeleteVote(reviewID int64, voterID string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM review_votes WHERE review_id = $1 AND voter_id = $2`, reviewID, voterID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddReaction 添加表情回应，重复添加不报错
+func (r *reviewRepository) AddReaction(reviewID int64, raterID, reaction string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO review_reactions (review_id, rater_id, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, reviewID, raterID, reaction)
+	return err
+}
+
+// RemoveReaction 移除表情回应，返回是否有记录被删除
+func (r *reviewRepository) RemoveReaction(reviewID int64, raterID, reaction string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM review_reactions WHERE review_id = $1 AND rater_id = $2 AND reaction = $3`, reviewID, raterID, reaction)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// insertModerationEvent 在事务中记录一次审核状态变化，from为空表示没有之前的状态
+func insertModerationEvent(tx *sql.Tx, reviewID int64, from, to, moderator, reason string) error {
+	var fromStatus *string
+	if from != "" {
+		fromStatus = &from
+	}
+
+	// 同时记录电影和评分者，评论随评分删除后审核记录仍可追溯
+	_, err := tx.Exec(`
+		INSERT INTO review_moderation_events (review_id, movie_title, rater_id, from_status, to_status, moderator, reason)
+		SELECT id, movie_title, rater_id, $2, $3, $4, $5 FROM reviews WHERE id = $1
+	`, reviewID, fromStatus, to, moderator, reason)
+	return err
+}
+
+// SetStatus 将评论从from状态改为to状态并记录审核事件；评论离开pending以外的审核时处理掉未处理的举报。
+// 评论当前状态不是from时（已被并发修改）返回false
+func (r *reviewRepository) SetStatus(reviewID int64, from, to, moderator, reason string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE reviews SET status = $3 WHERE id = $1 AND status = $2`, reviewID, from, to)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if err := insertModerationEvent(tx, reviewID, from, to, moderator, reason); err != nil {
+		return false, err
+	}
+
+	if to != models.ReviewStatusPending {
+		_, err := tx.Exec(`
+			UPDATE review_reports SET status = 'resolved', resolved_at = CURRENT_TIMESTAMP
+			WHERE review_id = $1 AND status = 'open'
+		`, reviewID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// ListQueue 分页获取指定审核状态的评论，被举报多的优先，其次按提交时间先后
+func (r *reviewRepository) ListQueue(status string, limit int, cursor string) (*models.ModerationQueuePage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `SELECT ` + reviewColumns + `,
+		(SELECT COUNT(*) FROM review_reports rr WHERE rr.review_id = r.id AND rr.status = 'open') AS open_reports
+		` + reviewFrom + `
+		WHERE r.status = $2
+		ORDER BY open_reports DESC, r.updated_at ASC, r.id ASC
+		LIMIT $3 OFFSET $4`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, "", status, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ModerationQueueItem{}
+	for rows.Next() {
+		var openReports int
+		review, err := scanReview(rows, &openReports)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, models.ModerationQueueItem{Review: *review, OpenReports: openReports})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.ModerationQueuePage{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// ListModerationEvents 按时间顺序获取评论的审核记录
+func (r *reviewRepository) ListModerationEvents(reviewID int64) ([]models.ReviewModerationEvent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, review_id, from_status, to_status, moderator, reason, created_at
+		FROM review_moderation_events
+		WHERE review_id = $1
+		ORDER BY id ASC
+	`, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ReviewModerationEvent{}
+	for rows.Next() {
+		var event models.ReviewModerationEvent
+		if err := rows.Scan(&event.ID, &event.ReviewID, &event.FromStatus, &event.ToStatus, &event.Moderator, &event.Reason, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// CreateReport 创建举报，同一评分者重复举报时返回唯一约束错误
+func (r *reviewRepository) CreateReport(report *models.ReviewReport) error {
+	query := `
+		INSERT INTO review_reports (review_id, movie_title, rater_id, reporter_id, reason, details)
+		SELECT id, movie_title, rater_id, $2, $3, $4 FROM reviews WHERE id = $1
+		RETURNING id, status, created_at
+	`
+
+	err := r.db.QueryRow(query, report.ReviewID, report.ReporterID, report.Reason, report.Details).
+		Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("review not found")
+	}
+	return err
+}
+
+// CountOpenReports 统计评论未处理的举报数
+func (r *reviewRepository) CountOpenReports(reviewID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM review_reports WHERE review_id = $1 AND status = 'open'`, reviewID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+)
+
+// RoleRepository 角色存储库接口
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	Exists(name string) (bool, error)
+	ListBySubject(subject string) ([]string, error)
+	ListAssignments(subject string) ([]models.RoleAssignment, error)
+	Assign(subject, role string) (*models.RoleAssignment, error)
+	Revoke(subject, role string) (bool, error)
+}
+
+// roleRepository 角色存储库实现
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository 创建角色存储库实例
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// List 列出所有角色及其权限
+func (r *roleRepository) List() ([]models.Role, error) {
+	query := `
+		SELECT r.name, r.description, rp.permission
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		ORDER BY r.name, rp.permission
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var name, description string
+		var permission sql.NullString
+		if err := rows.Scan(&name, &description, &permission); err != nil {
+			return nil, err
+		}
+		if len(roles) == 0 || roles[len(roles)-1].Name != name {
+			roles = append(roles, models.Role{Name: name, Description: description, Permissions: []string{}})
+		}
+		if permission.Valid {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission.String)
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+// Exists 判断角色是否存在
+func (r *roleRepository) Exists(name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)`, name).Scan(&exists)
+	return exists, err
+}
+
+// ListBySubject 获取主体在数据库中被授予的角色名
+func (r *roleRepository) ListBySubject(subject string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT role FROM role_assignments WHERE subject = $1 ORDER BY role`, subject)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// ListAssignments 获取主体的角色授予记录
+func (r *roleRepository) ListAssignments(subject string) ([]models.RoleAssignment, error) {
+	rows, err := r.db.Query(`SELECT subject, role, granted_at FROM role_assignments WHERE subject = $1 ORDER BY role`, subject)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []models.RoleAssignment{}
+	for rows.Next() {
+		var assignment models.RoleAssignment
+		if err := rows.Scan(&assignment.Subject, &assignment.Role, &assignment.GrantedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
+
+// Assign 授予角色，已授予时保持原授予时间
+func (r *roleRepository) Assign(subject, role string) (*models.RoleAssignment, error) {
+	query := `
+		INSERT INTO role_assignments (subject, role)
+		VALUES ($1, $2)
+		ON CONFLICT (subject, role) DO UPDATE SET subject = EXCLUDED.subject
+		RETURNING subject, role, granted_at
+	`
+
+	var assignment models.RoleAssignment
+	err := r.db.QueryRow(query, subject, role).Scan(&assignment.Subject, &assignment.Role, &assignment.GrantedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// Revoke 撤销角色
+func (r *roleRepository) Revoke(subject, role string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM role_assignments WHERE subject = $1 AND role = $2`, subject, role)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"movie-rating-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// SimilarityRepository 电影相似度存储库接口
+type SimilarityRepository interface {
+	ListRatings() ([]models.Rating, error)
+	ReplaceAll(similarities []models.MovieSimilarity) error
+	ListSimilar(movieTitle string, limit int) ([]models.SimilarMovie, error)
+	ListByAttributes(movie *models.Movie, limit int) ([]models.SimilarMovie, error)
+}
+
+// similarityRepository 电影相似度存储库实现
+type similarityRepository struct {
+	db *sql.DB
+}
+
+// NewSimilarityRepository 创建电影相似度存储库实例
+func NewSimilarityRepository(db *sql.DB) SimilarityRepository {
+	return &similarityRepository{db: db}
+}
+
+// ListRatings 获取计算相似度使用的全部评分，排除已确认的刷分评分
+func (r *similarityRepository) ListRatings() ([]models.Rating, error) {
+	query := `
+		SELECT r.movie_title, r.rater_id, r.rating
+		FROM ratings r
+		WHERE NOT EXISTS (
+			SELECT 1 FROM rating_flags f
+			WHERE f.movie_title = r.movie_title AND f.rater_id = r.rater_id AND f.status = 'confirmed'
+		)
+		ORDER BY r.rater_id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []models.Rating{}
+	for rows.Next() {
+		var rating models.Rating
+		if err := rows.Scan(&rating.MovieTitle, &rating.RaterID, &rating.Rating); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+// ReplaceAll 在一个事务中用新的计算结果替换全部相似度，读取方在提交前始终看到上一次的结果
+func (r *similarityRepository) ReplaceAll(similarities []models.MovieSimilarity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM movie_similarities`); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("movie_similarities", "movie_title", "similar_title", "score", "common_raters"))
+	if err != nil {
+		return err
+	}
+	for _, similarity := range similarities {
+		if _, err := stmt.Exec(similarity.MovieTitle, similarity.SimilarTitle, similarity.Score, similarity.CommonRaters); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	// 无参数的Exec将缓冲的数据写入数据库
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListSimilar 按相似度从高到低获取与电影相似的电影
+func (r *similarityRepository) ListSimilar(movieTitle string, limit int) ([]models.SimilarMovie, error) {
+	query := `
+		SELECT m.title, m.release_date,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = m.id ORDER BY mg.position),
+		       s.score, s.common_raters
+		FROM movie_similarities s
+		JOIN movies m ON m.title = s.similar_title
+		WHERE s.movie_title = $1
+		ORDER BY s.score DESC, s.common_raters DESC, m.title ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, movieTitle, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := []models.SimilarMovie{}
+	for rows.Next() {
+		movie := models.SimilarMovie{Basis: models.SimilarityBasisRatings}
+		if err := rows.Scan(&movie.Title, &movie.ReleaseDate, pq.Array(&movie.Genres), &movie.Score, &movie.CommonRaters); err != nil {
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+
+	return movies, rows.Err()
+}
+
+// ListByAttributes 按类型重合度和发行商获取相似电影，用于还没有足够评分的电影。
+// 得分为共同类型占该电影类型的比例（权重0.8）加上同一发行商（权重0.2），同分时评分多的优先
+func (r *similarityRepository) ListByAttributes(movie *models.Movie, limit int) ([]models.SimilarMovie, error) {
+	query := `
+		WITH candidates AS (
+			SELECT m.id, m.title, m.release_date,
+			       0.8 * (SELECT COUNT(*) FROM movie_genres mg
+			              WHERE mg.movie_id = m.id
+			                AND mg.genre_id IN (SELECT genre_id FROM movie_genres WHERE movie_id = $1))::float
+			           / GREATEST((SELECT COUNT(*) FROM movie_genres WHERE movie_id = $1), 1)
+			       + CASE WHEN $2::text IS NOT NULL AND m.distributor = $2 THEN 0.2 ELSE 0 END AS score
+			FROM movies m
+			WHERE m.id <> $1
+		)
+		SELECT c.title, c.release_date,
+		       ARRAY(SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id WHERE mg.movie_id = c.id ORDER BY mg.position),
+		       c.score
+		FROM candidates c
+		LEFT JOIN movie_rating_stats s ON s.movie_title = c.title
+		WHERE c.score > 0
+		ORDER BY c.score DESC, COALESCE(s.rating_count, 0) DESC, c.title ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, movie.ID, movie.Distributor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := []models.SimilarMovie{}
+	for rows.Next() {
+		similar := models.SimilarMovie{Basis: models.SimilarityBasisAttributes}
+		if err := rows.Scan(&similar.Title, &similar.ReleaseDate, pq.Array(&similar.Genres), &similar.Score); err != nil {
+			return nil, err
+		}
+		movies = append(movies, similar)
+	}
+
+	return movies, rows.Err()
+}
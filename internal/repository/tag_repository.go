@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"movie-rating-api/internal/models"
+)
+
+// TagRepository 电影标签存储库接口
+type TagRepository interface {
+	Add(movieID, raterID, name string) (bool, error)
+	Remove(movieID, raterID, key string) (bool, error)
+	ListByMovie(movieID, viewerID string, limit int) ([]models.MovieTag, error)
+	GetMovieTag(movieID, key, viewerID string) (*models.MovieTag, error)
+	ListPopular(prefix string, limit int, cursor string) (*models.TagPage, error)
+	ListTrending(since time.Time, limit int) ([]models.Tag, error)
+}
+
+// tagRepository 电影标签存储库实现
+type tagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository 创建电影标签存储库实例
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Add 评分者为电影添加标签，标签不存在时以name作为规范写法创建。
+// 返回是否新添加（评分者已添加过同一标签时为false）
+func (r *tagRepository) Add(movieID, raterID, name string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// 已存在时做一次空更新以便RETURNING取回id
+	var tagID int64
+	err = tx.QueryRow(`
+		INSERT INTO tags (name, key) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+		RETURNING id
+	`, name, models.TagKey(name)).Scan(&tagID)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO movie_tags (movie_id, tag_id, rater_id) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, movieID, tagID, raterID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, tx.Commit()
+}
+
+// Remove 移除评分者为电影添加的标签，返回是否有记录被删除
+func (r *tagRepository) Remove(movieID, raterID, key string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM movie_tags
+		WHERE movie_id = $1 AND rater_id = $2
+		  AND tag_id = (SELECT id FROM tags WHERE key = $3)
+	`, movieID, raterID, key)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// movieTagSelect 电影标签及添加人数，$1为电影ID，$2为调用方评分者ID（匿名为空）
+const movieTagSelect = `
+	SELECT t.name, COUNT(*), BOOL_OR(mt.rater_id = $2)
+	FROM movie_tags mt
+	JOIN tags t ON t.id = mt.tag_id
+	WHERE mt.movie_id = $1`
+
+// scanMovieTag 扫描一行电影标签，viewerID为空时不返回applied
+func scanMovieTag(scanner interface{ Scan(...interface{}) error }, viewerID string) (*models.MovieTag, error) {
+	var tag models.MovieTag
+	var applied bool
+	if err := scanner.Scan(&tag.Name, &tag.Count, &applied); err != nil {
+		return nil, err
+	}
+	if viewerID != "" {
+		tag.Applied = &applied
+	}
+	return &tag, nil
+}
+
+// ListByMovie 获取电影上添加人数最多的标签
+func (r *tagRepository) ListByMovie(movieID, viewerID string, limit int) ([]models.MovieTag, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := movieTagSelect + `
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(*) DESC, t.name ASC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, movieID, viewerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.MovieTag{}
+	for rows.Next() {
+		tag, err := scanMovieTag(rows, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetMovieTag 获取电影上的单个标签，没有评分者添加过时返回nil
+func (r *tagRepository) GetMovieTag(movieID, key, viewerID string) (*models.MovieTag, error) {
+	query := movieTagSelect + ` AND t.key = $3
+		GROUP BY t.id, t.name`
+
+	tag, err := scanMovieTag(r.db.QueryRow(query, movieID, viewerID, key), viewerID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// ListPopular 分页列出使用次数最多的标签，prefix为归一化键的前缀，用于输入补全
+func (r *tagRepository) ListPopular(prefix string, limit int, cursor string) (*models.TagPage, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := decodeOffsetCursor(cursor)
+
+	query := `
+		SELECT t.name, COUNT(DISTINCT mt.movie_id), COUNT(*)
+		FROM tags t
+		JOIN movie_tags mt ON mt.tag_id = t.id
+		WHERE t.key LIKE $1 || '%'
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(*) DESC, t.name ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	// 获取多一行用于判断是否有下一页
+	rows, err := r.db.Query(query, prefix, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Name, &tag.MovieCount, &tag.UseCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.TagPage{Items: tags}
+	if len(tags) > limit {
+		result.Items = tags[:limit]
+		nextCursor := encodeOffsetCursor(offset + limit)
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+// ListTrending 列出指定时间之后添加次数最多的标签
+func (r *tagRepository) ListTrending(since time.Time, limit int) ([]models.Tag, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := `
+		SELECT t.name, COUNT(DISTINCT mt.movie_id), COUNT(*)
+		FROM movie_tags mt
+		JOIN tags t ON t.id = mt.tag_id
+		WHERE mt.created_at >= $1
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(*) DESC, t.name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Name, &tag.MovieCount, &tag.UseCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
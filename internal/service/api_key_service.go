@@ -0,0 +1,132 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"strings"
+	"time"
+)
+
+// APIKeyService API密钥服务接口
+type APIKeyService interface {
+	CreateKey(keyCreate *models.APIKeyCreate) (*models.APIKeyCreated, error)
+	ListKeys() ([]models.APIKey, error)
+	RevokeKey(id string) error
+	Authenticate(rawKey string) (*models.APIKey, error)
+}
+
+// apiKeyService API密钥服务实现
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService 创建API密钥服务实例
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
+
+// CreateKey 创建API密钥，返回的明文密钥不会被保存
+func (s *apiKeyService) CreateKey(keyCreate *models.APIKeyCreate) (*models.APIKeyCreated, error) {
+	name := strings.TrimSpace(keyCreate.Name)
+	if name == "" {
+		return nil, fmt.Errorf("key name is required")
+	}
+	if len(keyCreate.Scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range keyCreate.Scopes {
+		if !isValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope '%s', must be one of: %s", scope, strings.Join(models.APIKeyScopes, ", "))
+		}
+	}
+	if keyCreate.ExpiresAt != nil && !keyCreate.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("invalid expiresAt: must be in the future")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	rawKey := models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		ID:        "key_" + hex.EncodeToString(id),
+		Name:      name,
+		Prefix:    rawKey[:len(models.APIKeyPrefix)+6],
+		KeyHash:   hashAPIKey(rawKey),
+		Scopes:    keyCreate.Scopes,
+		Subject:   keyCreate.Subject,
+		ExpiresAt: keyCreate.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreated{APIKey: *key, Key: rawKey}, nil
+}
+
+// ListKeys 列出所有API密钥
+func (s *apiKeyService) ListKeys() ([]models.APIKey, error) {
+	return s.apiKeyRepo.List()
+}
+
+// RevokeKey 吊销API密钥
+func (s *apiKeyService) RevokeKey(id string) error {
+	revoked, err := s.apiKeyRepo.Revoke(id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fmt.Errorf("api key not found or already revoked")
+	}
+	return nil
+}
+
+// Authenticate 校验明文API密钥：未知密钥返回nil，已吊销或已过期返回错误，
+// 校验通过时记录最后使用时间
+func (s *apiKeyService) Authenticate(rawKey string) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByHash(hashAPIKey(rawKey))
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("api key has been revoked")
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, fmt.Errorf("api key has expired")
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(key.ID); err != nil {
+		// 更新使用时间失败不影响认证
+		fmt.Printf("Error updating api key last used time: %v\n", err)
+	}
+
+	return key, nil
+}
+
+// hashAPIKey 计算明文密钥的SHA-256哈希；密钥本身是32字节随机数，无需慢哈希
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// isValidScope 判断权限范围是否合法
+func isValidScope(scope string) bool {
+	for _, s := range models.APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rolePermissionsTTL 角色-权限矩阵的缓存时间，矩阵只随迁移变化，短缓存即可避免每个请求查库
+const rolePermissionsTTL = 30 * time.Second
+
+// AuthorizationService 授权策略服务：解析主体的角色和权限，管理角色授予
+type AuthorizationService interface {
+	Resolve(subject string, baseRoles []string) ([]string, []string, error)
+	ListRoles() ([]models.Role, error)
+	ListSubjectRoles(subject string) ([]models.RoleAssignment, error)
+	AssignRole(subject, role string) (*models.RoleAssignment, error)
+	RevokeRole(subject, role string) error
+}
+
+// authorizationService 授权策略服务实现
+type authorizationService struct {
+	roleRepo repository.RoleRepository
+
+	mu          sync.Mutex
+	permissions map[string][]string
+	loadedAt    time.Time
+}
+
+// NewAuthorizationService 创建授权策略服务实例
+func NewAuthorizationService(roleRepo repository.RoleRepository) AuthorizationService {
+	return &authorizationService{roleRepo: roleRepo}
+}
+
+// Resolve 计算主体的有效角色和权限。baseRoles为凭证本身携带的角色（静态token、
+// API密钥范围或JWT的roles声明），有身份的主体还会合并数据库中授予的角色，
+// 并默认具有rater角色
+func (s *authorizationService) Resolve(subject string, baseRoles []string) ([]string, []string, error) {
+	roleSet := make(map[string]bool)
+	for _, role := range baseRoles {
+		roleSet[role] = true
+	}
+
+	if subject != "" {
+		roleSet[models.UserRoleRater] = true
+
+		granted, err := s.roleRepo.ListBySubject(subject)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, role := range granted {
+			roleSet[role] = true
+		}
+	}
+
+	matrix, err := s.rolePermissions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roles := make([]string, 0, len(roleSet))
+	permSet := make(map[string]bool)
+	for role := range roleSet {
+		// 凭证声明了数据库中不存在的角色时忽略
+		perms, ok := matrix[role]
+		if !ok {
+			continue
+		}
+		roles = append(roles, role)
+		for _, perm := range perms {
+			permSet[perm] = true
+		}
+	}
+
+	permissions := make([]string, 0, len(permSet))
+	for perm := range permSet {
+		permissions = append(permissions, perm)
+	}
+
+	sort.Strings(roles)
+	sort.Strings(permissions)
+	return roles, permissions, nil
+}
+
+// rolePermissions 获取角色-权限矩阵（带缓存）
+func (s *authorizationService) rolePermissions() (map[string][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.permissions != nil && time.Since(s.loadedAt) < rolePermissionsTTL {
+		return s.permissions, nil
+	}
+
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := make(map[string][]string, len(roles))
+	for _, role := range roles {
+		matrix[role.Name] = role.Permissions
+	}
+	s.permissions = matrix
+	s.loadedAt = time.Now()
+
+	return matrix, nil
+}
+
+// ListRoles 列出角色及其权限
+func (s *authorizationService) ListRoles() ([]models.Role, error) {
+	return s.roleRepo.List()
+}
+
+// ListSubjectRoles 列出主体在数据库中被授予的角色
+func (s *authorizationService) ListSubjectRoles(subject string) ([]models.RoleAssignment, error) {
+	return s.roleRepo.ListAssignments(subject)
+}
+
+// AssignRole 授予主体角色
+func (s *authorizationService) AssignRole(subject, role string) (*models.RoleAssignment, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, fmt.Errorf("subject is required")
+	}
+
+	exists, err := s.roleRepo.Exists(role)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	return s.roleRepo.Assign(subject, role)
+}
+
+// RevokeRole 撤销主体的角色
+func (s *authorizationService) RevokeRole(subject, role string) error {
+	revoked, err := s.roleRepo.Revoke(subject, role)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fmt.Errorf("role assignment not found")
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"strings"
+	"time"
+)
+
+// CollectionService 电影合集服务接口
+type CollectionService interface {
+	CreateCollection(collectionCreate *models.CollectionCreate) (*models.Collection, error)
+	GetCollection(id string) (*models.CollectionDetail, error)
+	ListCollections(limit int, cursor string) (*models.CollectionPage, error)
+	DeleteCollection(id string) error
+	AddMovie(collectionID string, memberAdd *models.CollectionMemberAdd) (*models.CollectionDetail, error)
+	RemoveMovie(collectionID, movieTitle string) error
+}
+
+// collectionService 电影合集服务实现
+type collectionService struct {
+	collectionRepo repository.CollectionRepository
+	movieRepo      repository.MovieRepository
+}
+
+// NewCollectionService 创建电影合集服务实例
+func NewCollectionService(collectionRepo repository.CollectionRepository, movieRepo repository.MovieRepository) CollectionService {
+	return &collectionService{
+		collectionRepo: collectionRepo,
+		movieRepo:      movieRepo,
+	}
+}
+
+// CreateCollection 创建合集
+func (s *collectionService) CreateCollection(collectionCreate *models.CollectionCreate) (*models.Collection, error) {
+	name := strings.TrimSpace(collectionCreate.Name)
+	if name == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+
+	id, err := generateCollectionID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.Collection{
+		ID:          id,
+		Name:        name,
+		Description: collectionCreate.Description,
+	}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// GetCollection 获取合集详情，包括成员及合集层面的聚合数据
+func (s *collectionService) GetCollection(id string) (*models.CollectionDetail, error) {
+	collection, err := s.collectionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection not found")
+	}
+
+	members, err := s.collectionRepo.ListMembers(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CollectionDetail{
+		Collection: *collection,
+		Members:    members,
+		Aggregates: aggregateCollection(members),
+	}, nil
+}
+
+// ListCollections 列出合集
+func (s *collectionService) ListCollections(limit int, cursor string) (*models.CollectionPage, error) {
+	return s.collectionRepo.List(limit, cursor)
+}
+
+// DeleteCollection 删除合集
+func (s *collectionService) DeleteCollection(id string) error {
+	deleted, err := s.collectionRepo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("collection not found")
+	}
+	return nil
+}
+
+// AddMovie 将电影加入合集
+func (s *collectionService) AddMovie(collectionID string, memberAdd *models.CollectionMemberAdd) (*models.CollectionDetail, error) {
+	if memberAdd.Position != nil && *memberAdd.Position < 1 {
+		return nil, fmt.Errorf("invalid position: must be at least 1")
+	}
+
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection not found")
+	}
+
+	movie, err := s.getMovie(memberAdd.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.collectionRepo.AddMember(collectionID, movie.ID, memberAdd.Position); err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(collectionID)
+}
+
+// RemoveMovie 将电影移出合集
+func (s *collectionService) RemoveMovie(collectionID, movieTitle string) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.collectionRepo.RemoveMember(collectionID, movie.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("movie not found in collection")
+	}
+	return nil
+}
+
+// getMovie 根据标题（或别名）获取电影
+func (s *collectionService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}
+
+// aggregateCollection 计算合集的票房总和与平均评分
+func aggregateCollection(members []models.CollectionMember) models.CollectionAggregates {
+	aggregates := models.CollectionAggregates{MovieCount: len(members)}
+
+	var ratingSum float64
+	var ratedMovies int
+	for _, member := range members {
+		if member.Movie.BoxOffice != nil {
+			aggregates.WorldwideBoxOffice += member.Movie.BoxOffice.Revenue.Worldwide
+			aggregates.MoviesWithBoxOffice++
+		}
+		if member.Rating.Count > 0 {
+			ratingSum += member.Rating.Average
+			ratedMovies++
+			aggregates.RatingCount += member.Rating.Count
+		}
+	}
+
+	if ratedMovies > 0 {
+		aggregates.AverageRating = ratingSum / float64(ratedMovies)
+	}
+	return aggregates
+}
+
+// generateCollectionID 生成合集ID，带随机后缀，避免同名合集并发创建时ID冲突
+func generateCollectionID(name string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	// 按字符而非字节截断，避免截断多字节字符
+	cleanName := []rune(strings.ReplaceAll(strings.ToLower(name), " ", "_"))
+	return fmt.Sprintf("c_%s_%d_%s", string(cleanName[:min(len(cleanName), 10)]), time.Now().UnixNano()/1000000, hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// ContentRatingService 内容分级服务接口
+type ContentRatingService interface {
+	ListSystems() ([]models.ContentRatingSystem, error)
+	ListMovieRatings(movieTitle string) ([]models.ContentRating, error)
+	SetMovieRating(movieTitle, system, rating string) (*models.ContentRating, bool, error)
+	DeleteMovieRating(movieTitle, system string) error
+}
+
+// contentRatingService 内容分级服务实现
+type contentRatingService struct {
+	contentRatingRepo repository.ContentRatingRepository
+	movieRepo         repository.MovieRepository
+}
+
+// NewContentRatingService 创建内容分级服务实例
+func NewContentRatingService(contentRatingRepo repository.ContentRatingRepository, movieRepo repository.MovieRepository) ContentRatingService {
+	return &contentRatingService{
+		contentRatingRepo: contentRatingRepo,
+		movieRepo:         movieRepo,
+	}
+}
+
+// ListSystems 列出支持的分级制度及其级别
+func (s *contentRatingService) ListSystems() ([]models.ContentRatingSystem, error) {
+	return s.contentRatingRepo.ListSystems()
+}
+
+// ListMovieRatings 获取电影在各分级制度下的级别
+func (s *contentRatingService) ListMovieRatings(movieTitle string) ([]models.ContentRating, error) {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.contentRatingRepo.ListByMovie(movie.ID)
+}
+
+// SetMovieRating 设置电影在某分级制度下的级别，返回标准化后的分级以及是否新建
+func (s *contentRatingService) SetMovieRating(movieTitle, system, rating string) (*models.ContentRating, bool, error) {
+	contentRating, err := resolveContentRating(s.contentRatingRepo, system, rating)
+	if err != nil {
+		return nil, false, err
+	}
+
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created, err := s.contentRatingRepo.Set(movie.ID, contentRating.System, contentRating.Rating)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return contentRating, created, nil
+}
+
+// DeleteMovieRating 删除电影在某分级制度下的级别
+func (s *contentRatingService) DeleteMovieRating(movieTitle, system string) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.contentRatingRepo.Delete(movie.ID, strings.ToUpper(system))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("content rating not found")
+	}
+	return nil
+}
+
+// getMovie 根据标题或别名获取电影
+func (s *contentRatingService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}
+
+// resolveContentRating 校验分级制度和级别，返回使用标准代码的分级
+func resolveContentRating(repo repository.ContentRatingRepository, system, rating string) (*models.ContentRating, error) {
+	ratingSystem, err := repo.GetSystem(strings.TrimSpace(system))
+	if err != nil {
+		return nil, err
+	}
+	if ratingSystem == nil {
+		return nil, fmt.Errorf("unknown content rating system '%s'", system)
+	}
+
+	level := ratingSystem.FindLevel(rating)
+	if level == nil {
+		return nil, fmt.Errorf("invalid content rating '%s' for %s, must be one of: %s",
+			rating, ratingSystem.Code, strings.Join(ratingSystem.LevelCodes(), ", "))
+	}
+
+	return &models.ContentRating{
+		System: ratingSystem.Code,
+		Region: ratingSystem.Region,
+		Rating: level.Code,
+		MinAge: level.MinAge,
+	}, nil
+}
+
+// resolveContentRatings 校验创建请求中的分级，mpaRating视为MPA制度的分级；
+// 每个制度只能有一个级别，mpaRating与contentRatings中的MPA级别必须一致
+func resolveContentRatings(repo repository.ContentRatingRepository, movieCreate *models.MovieCreate) ([]models.ContentRating, error) {
+	inputs := movieCreate.ContentRatings
+	if movieCreate.MPARating != nil && strings.TrimSpace(*movieCreate.MPARating) != "" {
+		inputs = append([]models.ContentRatingInput{{System: models.ContentRatingSystemMPA, Rating: *movieCreate.MPARating}}, inputs...)
+	}
+
+	ratings := []models.ContentRating{}
+	bySystem := make(map[string]string)
+	for _, input := range inputs {
+		contentRating, err := resolveContentRating(repo, input.System, input.Rating)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing, ok := bySystem[contentRating.System]; ok {
+			if existing == contentRating.Rating {
+				continue
+			}
+			return nil, fmt.Errorf("conflicting content ratings for %s: '%s' and '%s'", contentRating.System, existing, contentRating.Rating)
+		}
+		bySystem[contentRating.System] = contentRating.Rating
+		ratings = append(ratings, *contentRating)
+	}
+
+	return ratings, nil
+}
+
+// mpaRatingOf 返回分级中MPA制度的级别，用于填充兼容字段mpaRating
+func mpaRatingOf(ratings []models.ContentRating) *string {
+	for _, rating := range ratings {
+		if rating.System == models.ContentRatingSystemMPA {
+			mpaRating := rating.Rating
+			return &mpaRating
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的导出格式
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+// exportFlushInterval 每写出多少行刷新一次输出
+const exportFlushInterval = 500
+
+// movieExportColumns 电影CSV导出的列，genres用 | 分隔，contentRatings为 | 分隔的
+// SYSTEM:RATING，可直接用于批量导入
+var movieExportColumns = []string{
+	"id", "title", "releaseDate", "genre", "genres", "distributor", "budget", "mpaRating", "contentRatings",
+	"ratingAverage", "ratingCount",
+	"boxOfficeWorldwide", "boxOfficeOpeningWeekendUSA", "boxOfficeCurrency", "boxOfficeSource", "boxOfficeLastUpdated",
+}
+
+// ratingExportColumns 评分CSV导出的列
+var ratingExportColumns = []string{"movieTitle", "raterId", "rating", "createdAt", "updatedAt"}
+
+// ExportService 数据导出服务接口
+type ExportService interface {
+	ValidateMovieQuery(query map[string]interface{}) error
+	ExportMovies(w io.Writer, format string, query map[string]interface{}) error
+	ExportRatings(w io.Writer, format string, movieTitle, raterID string) error
+}
+
+// exportService 数据导出服务实现
+type exportService struct {
+	exportRepo repository.ExportRepository
+}
+
+// NewExportService 创建数据导出服务实例
+func NewExportService(exportRepo repository.ExportRepository) ExportService {
+	return &exportService{exportRepo: exportRepo}
+}
+
+// flusher 支持刷新缓冲区的输出（如HTTP响应）
+type flusher interface {
+	Flush()
+}
+
+// rowWriter 按格式逐行写出记录
+type rowWriter struct {
+	w      io.Writer
+	csv    *csv.Writer
+	json   *json.Encoder
+	format string
+	count  int
+}
+
+// newRowWriter 创建逐行写出器，CSV格式会先写出表头
+func newRowWriter(w io.Writer, format string, columns []string) (*rowWriter, error) {
+	rw := &rowWriter{w: w, format: format}
+	switch format {
+	case ExportFormatCSV:
+		rw.csv = csv.NewWriter(w)
+		if err := rw.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	case ExportFormatJSONL:
+		rw.json = json.NewEncoder(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+	return rw, nil
+}
+
+// write 写出一行，csvRecord只在CSV格式下使用
+func (rw *rowWriter) write(record interface{}, csvRecord func() []string) error {
+	if rw.csv != nil {
+		if err := rw.csv.Write(csvRecord()); err != nil {
+			return err
+		}
+	} else if err := rw.json.Encode(record); err != nil {
+		return err
+	}
+
+	rw.count++
+	if rw.count%exportFlushInterval == 0 {
+		return rw.flush()
+	}
+	return nil
+}
+
+// flush 将已写出的数据推送给客户端
+func (rw *rowWriter) flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		if err := rw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if f, ok := rw.w.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// ValidateMovieQuery 在开始输出前校验电影导出的排序参数，流式输出开始后无法再返回错误状态码
+func (s *exportService) ValidateMovieQuery(query map[string]interface{}) error {
+	return repository.ValidateMovieListOrder(query)
+}
+
+// ExportMovies 导出电影及其评分聚合和票房数据
+func (s *exportService) ExportMovies(w io.Writer, format string, query map[string]interface{}) error {
+	rw, err := newRowWriter(w, format, movieExportColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.exportRepo.StreamMovies(query, func(movie *models.MovieExport) error {
+		return rw.write(movie, func() []string { return movieCSVRecord(movie) })
+	})
+	if err != nil {
+		return err
+	}
+
+	return rw.flush()
+}
+
+// ExportRatings 导出原始评分，便于离线分析
+func (s *exportService) ExportRatings(w io.Writer, format string, movieTitle, raterID string) error {
+	rw, err := newRowWriter(w, format, ratingExportColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.exportRepo.StreamRatings(movieTitle, raterID, func(rating *models.RatingExport) error {
+		return rw.write(rating, func() []string {
+			return []string{
+				rating.MovieTitle,
+				rating.RaterID,
+				strconv.FormatFloat(rating.Rating, 'f', 1, 64),
+				rating.CreatedAt.UTC().Format(time.RFC3339),
+				rating.UpdatedAt.UTC().Format(time.RFC3339),
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return rw.flush()
+}
+
+// movieCSVRecord 将电影导出记录转换为CSV行
+func movieCSVRecord(movie *models.MovieExport) []string {
+	record := []string{
+		movie.ID,
+		movie.Title,
+		movie.ReleaseDate,
+		movie.Genre,
+		strings.Join(movie.Genres, "|"),
+		stringOrEmpty(movie.Distributor),
+		int64OrEmpty(movie.Budget),
+		stringOrEmpty(movie.MPARating),
+		contentRatingsCSV(movie.ContentRatings),
+		strconv.FormatFloat(movie.Rating.Average, 'f', -1, 64),
+		strconv.Itoa(movie.Rating.Count),
+		"", "", "", "", "",
+	}
+
+	if bo := movie.BoxOffice; bo != nil {
+		record[11] = strconv.FormatInt(bo.Revenue.Worldwide, 10)
+		record[12] = int64OrEmpty(bo.Revenue.OpeningWeekendUSA)
+		record[13] = bo.Currency
+		record[14] = bo.Source
+		record[15] = bo.LastUpdated.UTC().Format(time.RFC3339)
+	}
+
+	return record
+}
+
+// contentRatingsCSV 将分级格式化为 | 分隔的 SYSTEM:RATING
+func contentRatingsCSV(ratings []models.ContentRating) string {
+	entries := make([]string, 0, len(ratings))
+	for _, rating := range ratings {
+		entries = append(entries, rating.System+":"+rating.Rating)
+	}
+	return strings.Join(entries, "|")
+}
+
+// stringOrEmpty 返回字符串指针的值，nil时返回空字符串
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// int64OrEmpty 格式化整数指针，nil时返回空字符串
+func int64OrEmpty(n *int64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(*n, 10)
+}
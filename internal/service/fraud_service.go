@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"time"
+)
+
+// FraudDetectionConfig 刷分检测阈值
+type FraudDetectionConfig struct {
+	// Window 检测窗口
+	Window time.Duration
+	// BaselineWindow 计算正常提交速率的历史区间（窗口之前）
+	BaselineWindow time.Duration
+	// MinBurst 窗口内评分数低于此值时不做检测
+	MinBurst int
+	// SpikeFactor 窗口内评分数超过基线期望值的倍数时视为速率异常
+	SpikeFactor float64
+	// MinExpected 基线期望值的下限，避免历史提交很少的电影稍有提交就被视为速率异常
+	MinExpected float64
+	// NewRaterAge 提交时首次评分不超过此时长的评分者视为新评分者
+	NewRaterAge time.Duration
+	// NewRaterShare 新评分者在窗口内的占比阈值
+	NewRaterShare float64
+	// IdenticalShare 同一分数在窗口内的占比阈值
+	IdenticalShare float64
+}
+
+// DefaultFraudDetectionConfig 默认检测阈值
+func DefaultFraudDetectionConfig() FraudDetectionConfig {
+	return FraudDetectionConfig{
+		Window:         10 * time.Minute,
+		BaselineWindow: 7 * 24 * time.Hour,
+		MinBurst:       10,
+		SpikeFactor:    5,
+		MinExpected:    1,
+		NewRaterAge:    24 * time.Hour,
+		NewRaterShare:  0.6,
+		IdenticalShare: 0.8,
+	}
+}
+
+// FraudService 刷分检测及审核服务接口
+type FraudService interface {
+	Analyze(movieTitle string) (int, error)
+	ListFlags(status string, limit int, cursor string) (*models.RatingFlagPage, error)
+	ReviewFlag(id int64, status, reviewer string) (*models.RatingFlag, error)
+}
+
+// fraudService 刷分检测及审核服务实现
+type fraudService struct {
+	flagRepo repository.RatingFlagRepository
+	config   FraudDetectionConfig
+}
+
+// NewFraudService 创建刷分检测服务实例
+func NewFraudService(flagRepo repository.RatingFlagRepository, config FraudDetectionConfig) FraudService {
+	return &fraudService{
+		flagRepo: flagRepo,
+		config:   config,
+	}
+}
+
+// Analyze 分析电影最近的评分提交，将可疑评分加入审核队列，返回新增的标记数。
+// 检测三类模式：提交速率相对历史基线突增、新评分者集中涌入、同一分数集中出现
+func (s *fraudService) Analyze(movieTitle string) (int, error) {
+	activity, err := s.flagRepo.RecentActivity(movieTitle, s.config.Window)
+	if err != nil {
+		return 0, err
+	}
+	if len(activity) < s.config.MinBurst {
+		return 0, nil
+	}
+
+	var flags []models.RatingFlag
+	flag := func(a models.RatingActivity, reason, details string) {
+		flags = append(flags, models.RatingFlag{MovieTitle: movieTitle, RaterID: a.RaterID, Reason: reason, Details: details})
+	}
+	total := float64(len(activity))
+
+	// 速率突增：与历史同长度窗口的平均提交数比较。没有历史提交的电影（如新上映）
+	// 无从比较，跳过这条规则，仍按另外两类模式检测
+	baseline, err := s.flagRepo.CountSubmissions(movieTitle, s.config.BaselineWindow+s.config.Window, s.config.Window)
+	if err != nil {
+		return 0, err
+	}
+	expected := float64(baseline) * s.config.Window.Seconds() / s.config.BaselineWindow.Seconds()
+	if expected < s.config.MinExpected {
+		expected = s.config.MinExpected
+	}
+	if baseline > 0 && total > expected*s.config.SpikeFactor {
+		details := fmt.Sprintf("%d ratings in %s, baseline %.2f", len(activity), s.config.Window, expected)
+		for _, a := range activity {
+			flag(a, models.FlagReasonVelocitySpike, details)
+		}
+	}
+
+	// 新评分者集中涌入
+	var newRaters []models.RatingActivity
+	for _, a := range activity {
+		if a.SubmittedAt.Sub(a.RaterFirstSeen) < s.config.NewRaterAge {
+			newRaters = append(newRaters, a)
+		}
+	}
+	if float64(len(newRaters))/total >= s.config.NewRaterShare {
+		details := fmt.Sprintf("%d of %d ratings in %s from raters first seen within %s",
+			len(newRaters), len(activity), s.config.Window, s.config.NewRaterAge)
+		for _, a := range newRaters {
+			flag(a, models.FlagReasonNewRaterCluster, details)
+		}
+	}
+
+	// 同一分数集中出现
+	scoreCounts := make(map[float64]int)
+	topScore, topCount := 0.0, 0
+	for _, a := range activity {
+		scoreCounts[a.Rating]++
+		if scoreCounts[a.Rating] > topCount {
+			topScore, topCount = a.Rating, scoreCounts[a.Rating]
+		}
+	}
+	if float64(topCount)/total >= s.config.IdenticalShare {
+		details := fmt.Sprintf("%d of %d ratings in %s scored %.1f", topCount, len(activity), s.config.Window, topScore)
+		for _, a := range activity {
+			if a.Rating == topScore {
+				flag(a, models.FlagReasonIdenticalBurst, details)
+			}
+		}
+	}
+
+	return s.flagRepo.CreateFlags(flags)
+}
+
+// ListFlags 分页列出审核队列中的标记
+func (s *fraudService) ListFlags(status string, limit int, cursor string) (*models.RatingFlagPage, error) {
+	switch status {
+	case "", models.FlagStatusPending, models.FlagStatusConfirmed, models.FlagStatusCleared:
+	default:
+		return nil, fmt.Errorf("invalid status '%s'", status)
+	}
+
+	return s.flagRepo.List(status, limit, cursor)
+}
+
+// ReviewFlag 审核标记：confirmed表示确认为刷分（评分不再计入聚合），cleared表示评分正常
+func (s *fraudService) ReviewFlag(id int64, status, reviewer string) (*models.RatingFlag, error) {
+	if status != models.FlagStatusConfirmed && status != models.FlagStatusCleared {
+		return nil, fmt.Errorf("invalid status '%s'", status)
+	}
+
+	flag, err := s.flagRepo.Review(id, status, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, fmt.Errorf("rating flag not found")
+	}
+
+	return flag, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"strings"
+)
+
+// GenreService 电影类型服务接口
+type GenreService interface {
+	ListGenres() ([]models.Genre, error)
+	CreateGenre(genreCreate *models.GenreCreate) (*models.Genre, error)
+}
+
+// genreService 电影类型服务实现
+type genreService struct {
+	genreRepo repository.GenreRepository
+}
+
+// NewGenreService 创建电影类型服务实例
+func NewGenreService(genreRepo repository.GenreRepository) GenreService {
+	return &genreService{genreRepo: genreRepo}
+}
+
+// ListGenres 列出所有类型及其电影数量
+func (s *genreService) ListGenres() ([]models.Genre, error) {
+	return s.genreRepo.ListWithCounts()
+}
+
+// CreateGenre 向分类表中添加新类型
+func (s *genreService) CreateGenre(genreCreate *models.GenreCreate) (*models.Genre, error) {
+	name := strings.TrimSpace(genreCreate.Name)
+	if models.GenreKey(name) == "" {
+		return nil, fmt.Errorf("genre name is required")
+	}
+
+	// 名称及同义词都不能与已有类型冲突
+	for _, candidate := range append([]string{name}, genreCreate.Synonyms...) {
+		existing, err := s.genreRepo.Resolve(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("genre '%s' already exists as '%s'", candidate, existing.Name)
+		}
+	}
+
+	genre := &models.Genre{
+		Slug: models.GenreSlug(name),
+		Name: name,
+	}
+	if err := s.genreRepo.Create(genre, genreCreate.Synonyms); err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
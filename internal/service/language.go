@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// normalizeLanguageTag 校验并规范化BCP 47语言标签（如 zh-hans-cn -> zh-Hans-CN）
+func normalizeLanguageTag(tag string) (string, error) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return "", fmt.Errorf("invalid language code: empty")
+	}
+
+	subtags := strings.Split(tag, "-")
+	for i, sub := range subtags {
+		if len(sub) == 0 || len(sub) > 8 || !isAlphanumeric(sub) {
+			return "", fmt.Errorf("invalid language code: %s", tag)
+		}
+
+		switch {
+		case i == 0:
+			// 主语言子标签必须是2-3个字母
+			if len(sub) < 2 || len(sub) > 3 || !isAlpha(sub) {
+				return "", fmt.Errorf("invalid language code: %s", tag)
+			}
+			subtags[i] = strings.ToLower(sub)
+		case len(sub) == 4 && isAlpha(sub):
+			// 书写系统子标签，首字母大写（Hans、Latn）
+			subtags[i] = strings.ToUpper(sub[:1]) + strings.ToLower(sub[1:])
+		case len(sub) == 2 && isAlpha(sub), len(sub) == 3 && !isAlpha(sub):
+			// 地区子标签（CN、US、419）
+			subtags[i] = strings.ToUpper(sub)
+		default:
+			subtags[i] = strings.ToLower(sub)
+		}
+	}
+
+	return strings.Join(subtags, "-"), nil
+}
+
+// primaryLanguage 返回语言标签的主语言部分（zh-CN -> zh）
+func primaryLanguage(tag string) string {
+	if idx := strings.Index(tag, "-"); idx >= 0 {
+		return strings.ToLower(tag[:idx])
+	}
+	return strings.ToLower(tag)
+}
+
+// matchLanguage 按客户端偏好顺序在可用语言中选择最佳匹配，
+// 先尝试完全匹配，再退回到主语言匹配；没有匹配时返回空字符串
+func matchLanguage(preferred []string, available []string) string {
+	for _, pref := range preferred {
+		for _, lang := range available {
+			if strings.EqualFold(pref, lang) {
+				return lang
+			}
+		}
+		for _, lang := range available {
+			if primaryLanguage(pref) == primaryLanguage(lang) {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// isAlpha 判断字符串是否只包含ASCII字母
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlphanumeric 判断字符串是否只包含ASCII字母和数字
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
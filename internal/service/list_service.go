@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// ListService 评分者电影列表服务接口
+type ListService interface {
+	CreateList(ownerID string, listCreate *models.UserListCreate) (*models.UserList, error)
+	GetList(id string) (*models.UserList, error)
+	GetWatchlist(ownerID string, create bool) (*models.UserList, error)
+	ListLists(ownerID, viewerID string, limit int, cursor string) (*models.UserListPage, error)
+	UpdateList(id string, listUpdate *models.UserListUpdate) (*models.UserList, error)
+	DeleteList(id string) error
+	AddItem(listID string, itemAdd *models.UserListItemAdd) (*models.UserList, error)
+	RemoveItem(listID, movieTitle string) error
+	ListItems(listID string, limit int, cursor string) (*models.UserListItemPage, error)
+}
+
+// listService 评分者电影列表服务实现
+type listService struct {
+	listRepo  repository.ListRepository
+	movieRepo repository.MovieRepository
+}
+
+// NewListService 创建评分者电影列表服务实例
+func NewListService(listRepo repository.ListRepository, movieRepo repository.MovieRepository) ListService {
+	return &listService{
+		listRepo:  listRepo,
+		movieRepo: movieRepo,
+	}
+}
+
+// CreateList 为评分者创建自定义列表
+func (s *listService) CreateList(ownerID string, listCreate *models.UserListCreate) (*models.UserList, error) {
+	name := strings.TrimSpace(listCreate.Name)
+	if name == "" {
+		return nil, fmt.Errorf("list name is required")
+	}
+
+	visibility := listCreate.Visibility
+	if visibility == "" {
+		visibility = models.ListVisibilityPrivate
+	}
+	if !isValidListVisibility(visibility) {
+		return nil, fmt.Errorf("invalid visibility: must be 'public' or 'private'")
+	}
+
+	id, err := generateListID()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.UserList{
+		ID:          id,
+		OwnerID:     ownerID,
+		Name:        name,
+		Description: listCreate.Description,
+		Kind:        models.ListKindCustom,
+		Visibility:  visibility,
+	}
+	if err := s.listRepo.Create(list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetList 根据ID获取列表，可见性由调用方检查
+func (s *listService) GetList(id string) (*models.UserList, error) {
+	list, err := s.listRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+	return list, nil
+}
+
+// GetWatchlist 获取评分者的待看列表；create为true时在不存在时创建（私有）
+func (s *listService) GetWatchlist(ownerID string, create bool) (*models.UserList, error) {
+	if !create {
+		list, err := s.listRepo.GetWatchlist(ownerID)
+		if err != nil {
+			return nil, err
+		}
+		if list == nil {
+			return nil, fmt.Errorf("watchlist not found")
+		}
+		return list, nil
+	}
+
+	id, err := generateListID()
+	if err != nil {
+		return nil, err
+	}
+	return s.listRepo.EnsureWatchlist(&models.UserList{
+		ID:         id,
+		OwnerID:    ownerID,
+		Name:       "Watchlist",
+		Kind:       models.ListKindWatchlist,
+		Visibility: models.ListVisibilityPrivate,
+	})
+}
+
+// ListLists 分页列出公开列表及查看者自己的列表，ownerID非空时只列出该评分者的列表
+func (s *listService) ListLists(ownerID, viewerID string, limit int, cursor string) (*models.UserListPage, error) {
+	return s.listRepo.List(ownerID, viewerID, limit, cursor)
+}
+
+// UpdateList 更新列表，待看列表不能改名
+func (s *listService) UpdateList(id string, listUpdate *models.UserListUpdate) (*models.UserList, error) {
+	list, err := s.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if listUpdate.Name != nil {
+		name := strings.TrimSpace(*listUpdate.Name)
+		if name == "" {
+			return nil, fmt.Errorf("list name is required")
+		}
+		if list.Kind == models.ListKindWatchlist && name != list.Name {
+			return nil, fmt.Errorf("invalid name: the watchlist cannot be renamed")
+		}
+		list.Name = name
+	}
+	if listUpdate.Description != nil {
+		list.Description = listUpdate.Description
+	}
+	if listUpdate.Visibility != nil {
+		if !isValidListVisibility(*listUpdate.Visibility) {
+			return nil, fmt.Errorf("invalid visibility: must be 'public' or 'private'")
+		}
+		list.Visibility = *listUpdate.Visibility
+	}
+
+	if err := s.listRepo.Update(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteList 删除自定义列表，待看列表只能清空不能删除
+func (s *listService) DeleteList(id string) error {
+	list, err := s.GetList(id)
+	if err != nil {
+		return err
+	}
+	if list.Kind == models.ListKindWatchlist {
+		return fmt.Errorf("invalid operation: the watchlist cannot be deleted")
+	}
+
+	deleted, err := s.listRepo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("list not found")
+	}
+	return nil
+}
+
+// AddItem 将电影加入列表，返回更新后的列表
+func (s *listService) AddItem(listID string, itemAdd *models.UserListItemAdd) (*models.UserList, error) {
+	if itemAdd.Position != nil && *itemAdd.Position < 1 {
+		return nil, fmt.Errorf("invalid position: must be at least 1")
+	}
+
+	if _, err := s.GetList(listID); err != nil {
+		return nil, err
+	}
+
+	movie, err := s.getMovie(itemAdd.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.listRepo.AddItem(listID, movie.ID, itemAdd.Position, itemAdd.Note); err != nil {
+		return nil, err
+	}
+
+	return s.GetList(listID)
+}
+
+// RemoveItem 将电影移出列表
+func (s *listService) RemoveItem(listID, movieTitle string) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.listRepo.RemoveItem(listID, movie.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("movie not found in list")
+	}
+	return nil
+}
+
+// ListItems 分页获取列表中的电影
+func (s *listService) ListItems(listID string, limit int, cursor string) (*models.UserListItemPage, error) {
+	return s.listRepo.ListItems(listID, limit, cursor)
+}
+
+// getMovie 根据标题（或别名）获取电影
+func (s *listService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}
+
+// isValidListVisibility 判断列表可见性是否合法
+func isValidListVisibility(visibility string) bool {
+	return visibility == models.ListVisibilityPublic || visibility == models.ListVisibilityPrivate
+}
+
+// generateListID 生成列表ID
+func generateListID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return "list_" + hex.EncodeToString(id), nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"movie-rating-api/internal/models"
+	"strings"
+)
+
+// importBatchSize best-effort模式下每个事务包含的最大行数
+const importBatchSize = 500
+
+// ImportMovies 批量导入电影：逐行使用与CreateMovie相同的规则校验，
+// 已存在（或在本次导入中重复）的标题记为skipped，校验失败的行记为failed
+func (s *movieService) ImportMovies(r io.Reader, format string, opts models.ImportOptions) (*models.ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeAtomic
+	}
+
+	rows, err := ParseMovieImport(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{
+		Mode: opts.Mode,
+		Rows: make([]models.ImportRowResult, len(rows)),
+	}
+
+	titles := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.ParseError == nil && row.Movie.Title != "" {
+			titles = append(titles, row.Movie.Title)
+		}
+	}
+	existing, err := s.movieRepo.ExistingTitles(titles)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验每一行，收集待插入的电影及其在报告中的下标
+	var pending []*models.Movie
+	var pendingIndex []int
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Row = row.Row
+		result.Title = row.Movie.Title
+
+		movie, status, err := s.prepareImportRow(row, existing, seen, opts)
+		result.Status = status
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		if movie != nil {
+			pending = append(pending, movie)
+			pendingIndex = append(pendingIndex, i)
+		}
+	}
+
+	if opts.Mode == models.ImportModeAtomic {
+		if err := s.importAtomic(report, pending, pendingIndex); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := s.importBestEffort(report, pending, pendingIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, result := range report.Rows {
+		switch result.Status {
+		case models.ImportStatusCreated:
+			report.Created++
+		case models.ImportStatusSkipped:
+			report.Skipped++
+		case models.ImportStatusFailed:
+			report.Failed++
+		}
+	}
+	report.Total = len(report.Rows)
+
+	return report, nil
+}
+
+// prepareImportRow 校验单行并构建电影实例，返回nil电影表示该行不需要插入
+func (s *movieService) prepareImportRow(row models.ImportRow, existing, seen map[string]bool, opts models.ImportOptions) (*models.Movie, string, error) {
+	if row.ParseError != nil {
+		return nil, models.ImportStatusFailed, row.ParseError
+	}
+
+	movieCreate := row.Movie
+	if err := validateMovieCreate(&movieCreate); err != nil {
+		return nil, models.ImportStatusFailed, err
+	}
+
+	genres, err := s.resolveGenres(&movieCreate)
+	if err != nil {
+		return nil, models.ImportStatusFailed, err
+	}
+
+	contentRatings, err := resolveContentRatings(s.contentRatingRepo, &movieCreate)
+	if err != nil {
+		return nil, models.ImportStatusFailed, err
+	}
+
+	if existing[movieCreate.Title] {
+		return nil, models.ImportStatusSkipped, fmt.Errorf("movie with title '%s' already exists", movieCreate.Title)
+	}
+	if seen[movieCreate.Title] {
+		return nil, models.ImportStatusSkipped, fmt.Errorf("duplicate title '%s' in import", movieCreate.Title)
+	}
+	seen[movieCreate.Title] = true
+
+	movie := &models.Movie{
+		// 同一毫秒内会生成多个ID，附加行号保证唯一
+		ID:             fmt.Sprintf("%s_%d", generateMovieID(movieCreate.Title), row.Row),
+		Title:          movieCreate.Title,
+		ReleaseDate:    movieCreate.ReleaseDate,
+		Genre:          genres[0],
+		Genres:         genres,
+		Distributor:    movieCreate.Distributor,
+		Budget:         movieCreate.Budget,
+		ContentRatings: contentRatings,
+		MPARating:      mpaRatingOf(contentRatings),
+	}
+
+	if opts.FetchBoxOffice && s.boxOfficeService != nil {
+		boxOfficeData, err := s.boxOfficeService.GetBoxOfficeData(movieCreate.Title)
+		if err == nil && boxOfficeData != nil {
+			movie.BoxOffice = boxOfficeData
+		}
+	}
+
+	return movie, models.ImportStatusCreated, nil
+}
+
+// importAtomic 全部成功或全部回滚：任何一行校验或插入失败，其余行均不导入
+func (s *movieService) importAtomic(report *models.ImportReport, pending []*models.Movie, pendingIndex []int) error {
+	for _, result := range report.Rows {
+		if result.Status == models.ImportStatusFailed {
+			abortImport(report, pendingIndex, "not imported: batch rolled back because other rows failed")
+			return nil
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	rowErrors, err := s.movieRepo.CreateBatch(pending, true)
+	if err != nil {
+		return err
+	}
+
+	for i, rowErr := range rowErrors {
+		if rowErr != nil {
+			abortImport(report, pendingIndex, "not imported: batch rolled back because other rows failed")
+			result := &report.Rows[pendingIndex[i]]
+			result.Status = models.ImportStatusFailed
+			result.Error = describeInsertError(rowErr)
+			return nil
+		}
+	}
+
+	for i, movie := range pending {
+		report.Rows[pendingIndex[i]].ID = movie.ID
+	}
+	return nil
+}
+
+// importBestEffort 尽力导入：按批次提交，失败的行单独记录
+func (s *movieService) importBestEffort(report *models.ImportReport, pending []*models.Movie, pendingIndex []int) error {
+	for start := 0; start < len(pending); start += importBatchSize {
+		end := min(start+importBatchSize, len(pending))
+
+		rowErrors, err := s.movieRepo.CreateBatch(pending[start:end], false)
+		if err != nil {
+			return err
+		}
+
+		for i, rowErr := range rowErrors {
+			result := &report.Rows[pendingIndex[start+i]]
+			if rowErr != nil {
+				result.Status = models.ImportStatusFailed
+				result.Error = describeInsertError(rowErr)
+				continue
+			}
+			result.ID = pending[start+i].ID
+		}
+	}
+	return nil
+}
+
+// abortImport 将待插入的行标记为因整体回滚而跳过
+func abortImport(report *models.ImportReport, pendingIndex []int, reason string) {
+	for _, idx := range pendingIndex {
+		report.Rows[idx].Status = models.ImportStatusSkipped
+		report.Rows[idx].Error = reason
+	}
+}
+
+// describeInsertError 将数据库错误转换为面向用户的描述
+func describeInsertError(err error) string {
+	if strings.Contains(err.Error(), "duplicate key") {
+		return "movie already exists"
+	}
+	return fmt.Sprintf("failed to insert movie: %v", err)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"movie-rating-api/internal/models"
+	"strconv"
+	"strings"
+)
+
+// 支持的导入格式
+const (
+	ImportFormatJSON  = "json"
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+)
+
+// DetectImportFormat 根据显式指定的格式、Content-Type或文件名推断导入格式
+func DetectImportFormat(format, contentType, filename string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		return ImportFormatJSON, nil
+	case "csv":
+		return ImportFormatCSV, nil
+	case "jsonl", "ndjson":
+		return ImportFormatJSONL, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported import format '%s'", format)
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "application/json":
+		return ImportFormatJSON, nil
+	case "text/csv":
+		return ImportFormatCSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return ImportFormatJSONL, nil
+	}
+
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".json"):
+		return ImportFormatJSON, nil
+	case strings.HasSuffix(lower, ".csv"):
+		return ImportFormatCSV, nil
+	case strings.HasSuffix(lower, ".jsonl"), strings.HasSuffix(lower, ".ndjson"):
+		return ImportFormatJSONL, nil
+	}
+
+	return "", fmt.Errorf("unsupported import format: use format=json|csv|jsonl")
+}
+
+// ParseMovieImport 解析导入数据，单行的解析错误记录在对应行中，
+// 只有整体格式错误（如JSON数组不完整、CSV缺少表头）才返回error
+func ParseMovieImport(r io.Reader, format string) ([]models.ImportRow, error) {
+	switch format {
+	case ImportFormatJSON:
+		return parseJSONImport(r)
+	case ImportFormatCSV:
+		return parseCSVImport(r)
+	case ImportFormatJSONL:
+		return parseJSONLImport(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format '%s'", format)
+	}
+}
+
+// parseJSONImport 解析JSON数组
+func parseJSONImport(r io.Reader) ([]models.ImportRow, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid import body: expected a JSON array of movies: %v", err)
+	}
+
+	rows := make([]models.ImportRow, 0, len(raw))
+	for i, item := range raw {
+		rows = append(rows, decodeJSONRow(i+1, item))
+	}
+	return rows, nil
+}
+
+// parseJSONLImport 解析每行一个JSON对象的JSONL数据，忽略空行
+func parseJSONLImport(r io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var rows []models.ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		rows = append(rows, decodeJSONRow(line, text))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid import body: %v", err)
+	}
+	return rows, nil
+}
+
+// decodeJSONRow 解码单个JSON对象为电影创建请求
+func decodeJSONRow(row int, data []byte) models.ImportRow {
+	var movie models.MovieCreate
+	if err := json.Unmarshal(data, &movie); err != nil {
+		return models.ImportRow{Row: row, ParseError: fmt.Errorf("invalid JSON: %v", err)}
+	}
+	return models.ImportRow{Row: row, Movie: movie}
+}
+
+// parseCSVImport 解析带表头的CSV，表头同时接受camelCase和snake_case，
+// genres列中的多个类型用 | 分隔
+func parseCSVImport(r io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid import body: missing CSV header: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))
+		// 去掉可能存在的UTF-8 BOM
+		key = strings.TrimPrefix(key, "\ufeff")
+		columns[key] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("invalid import body: CSV header must include a title column")
+	}
+
+	var rows []models.ImportRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			// 只有格式错误记录在行中，读取失败（如请求体超过上限）中止整个导入
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("invalid import body: %v", err)
+			}
+			rows = append(rows, models.ImportRow{Row: line, ParseError: fmt.Errorf("invalid CSV row: %v", err)})
+			continue
+		}
+
+		get := func(column string) string {
+			if idx, ok := columns[column]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		movie := models.MovieCreate{
+			Title:       get("title"),
+			ReleaseDate: get("releasedate"),
+			Genre:       get("genre"),
+		}
+		if genres := get("genres"); genres != "" {
+			for _, genre := range strings.Split(genres, "|") {
+				if genre = strings.TrimSpace(genre); genre != "" {
+					movie.Genres = append(movie.Genres, genre)
+				}
+			}
+		}
+		if distributor := get("distributor"); distributor != "" {
+			movie.Distributor = &distributor
+		}
+		if mpaRating := get("mparating"); mpaRating != "" {
+			movie.MPARating = &mpaRating
+		}
+		// contentRatings列格式为 "SYSTEM:RATING|SYSTEM:RATING"，与导出格式一致
+		if contentRatings := get("contentratings"); contentRatings != "" {
+			parsed, err := parseContentRatingsColumn(contentRatings)
+			if err != nil {
+				rows = append(rows, models.ImportRow{Row: line, Movie: movie, ParseError: err})
+				continue
+			}
+			movie.ContentRatings = parsed
+		}
+		if budgetStr := get("budget"); budgetStr != "" {
+			budget, err := strconv.ParseInt(budgetStr, 10, 64)
+			if err != nil {
+				rows = append(rows, models.ImportRow{Row: line, Movie: movie, ParseError: fmt.Errorf("invalid budget '%s'", budgetStr)})
+				continue
+			}
+			movie.Budget = &budget
+		}
+
+		rows = append(rows, models.ImportRow{Row: line, Movie: movie})
+	}
+
+	return rows, nil
+}
+
+// parseContentRatingsColumn 解析CSV中 "SYSTEM:RATING|SYSTEM:RATING" 格式的分级列
+func parseContentRatingsColumn(value string) ([]models.ContentRatingInput, error) {
+	var ratings []models.ContentRatingInput
+	for _, entry := range strings.Split(value, "|") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		system, rating, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(system) == "" || strings.TrimSpace(rating) == "" {
+			return nil, fmt.Errorf("invalid content rating '%s', must be SYSTEM:RATING", entry)
+		}
+		ratings = append(ratings, models.ContentRatingInput{System: strings.TrimSpace(system), Rating: strings.TrimSpace(rating)})
+	}
+	return ratings, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"io"
 	"movie-rating-api/internal/models"
 	"movie-rating-api/internal/repository"
 	"strings"
@@ -11,44 +12,76 @@ import (
 // MovieService 电影服务接口
 type MovieService interface {
 	CreateMovie(movieCreate *models.MovieCreate) (*models.Movie, error)
+	ImportMovies(r io.Reader, format string, opts models.ImportOptions) (*models.ImportReport, error)
 	GetMovieByTitle(title string) (*models.Movie, error)
 	ListMovies(query map[string]interface{}, limit int, cursor string) (*models.MoviePage, error)
+	LocalizeMovies(movies []models.Movie, languages []string) error
+	ListAliases(title string) ([]models.MovieAlias, error)
+	AddAlias(title string, aliasCreate *models.MovieAliasCreate) (*models.MovieAlias, error)
+	DeleteAlias(title string, aliasID int64) error
 }
 
 // movieService 电影服务实现
 type movieService struct {
-	movieRepo        repository.MovieRepository
-	boxOfficeService BoxOfficeService
+	movieRepo         repository.MovieRepository
+	aliasRepo         repository.AliasRepository
+	genreRepo         repository.GenreRepository
+	contentRatingRepo repository.ContentRatingRepository
+	boxOfficeService  BoxOfficeService
+	rounding          string
 }
 
-// NewMovieService 创建电影服务实例
-func NewMovieService(movieRepo repository.MovieRepository, boxOfficeService BoxOfficeService) MovieService {
+// NewMovieService 创建电影服务实例，rounding为列表中平均评分使用的舍入方式
+func NewMovieService(movieRepo repository.MovieRepository, aliasRepo repository.AliasRepository, genreRepo repository.GenreRepository, contentRatingRepo repository.ContentRatingRepository, boxOfficeService BoxOfficeService, rounding string) MovieService {
 	return &movieService{
-		movieRepo:        movieRepo,
-		boxOfficeService: boxOfficeService,
+		movieRepo:         movieRepo,
+		aliasRepo:         aliasRepo,
+		genreRepo:         genreRepo,
+		contentRatingRepo: contentRatingRepo,
+		boxOfficeService:  boxOfficeService,
+		rounding:          rounding,
 	}
 }
 
 // CreateMovie 创建新电影
 func (s *movieService) CreateMovie(movieCreate *models.MovieCreate) (*models.Movie, error) {
-	// 检查电影是否已存在
+	// 验证必填字段和日期格式
+	if err := validateMovieCreate(movieCreate); err != nil {
+		return nil, err
+	}
+
+	// 检查电影是否已存在（只与正式标题冲突，与其他电影的别名同名是允许的）
 	existingMovie, err := s.movieRepo.GetByTitle(movieCreate.Title)
 	if err != nil {
 		return nil, err
 	}
-	if existingMovie != nil {
+	if existingMovie != nil && existingMovie.Title == movieCreate.Title {
 		return nil, fmt.Errorf("movie with title '%s' already exists", movieCreate.Title)
 	}
 
+	// 将输入的类型解析为分类表中的标准类型
+	genres, err := s.resolveGenres(movieCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验各分级制度下的级别，mpaRating作为MPA分级处理
+	contentRatings, err := resolveContentRatings(s.contentRatingRepo, movieCreate)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建电影实例
 	movie := &models.Movie{
-		ID:          generateMovieID(movieCreate.Title),
-		Title:       movieCreate.Title,
-		ReleaseDate: movieCreate.ReleaseDate,
-		Genre:       movieCreate.Genre,
-		Distributor: movieCreate.Distributor,
-		Budget:      movieCreate.Budget,
-		MPARating:   movieCreate.MPARating,
+		ID:             generateMovieID(movieCreate.Title),
+		Title:          movieCreate.Title,
+		ReleaseDate:    movieCreate.ReleaseDate,
+		Genre:          genres[0],
+		Genres:         genres,
+		Distributor:    movieCreate.Distributor,
+		Budget:         movieCreate.Budget,
+		ContentRatings: contentRatings,
+		MPARating:      mpaRatingOf(contentRatings),
 	}
 
 	// 尝试从票房API获取数据
@@ -82,7 +115,177 @@ func (s *movieService) GetMovieByTitle(title string) (*models.Movie, error) {
 
 // ListMovies 列出电影
 func (s *movieService) ListMovies(query map[string]interface{}, limit int, cursor string) (*models.MoviePage, error) {
-	return s.movieRepo.List(query, limit, cursor)
+	page, err := s.movieRepo.List(query, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range page.Items {
+		if page.Items[i].Ratings != nil {
+			page.Items[i].Ratings.Round(s.rounding)
+		}
+	}
+	return page, nil
+}
+
+// validateMovieCreate 验证创建电影请求的必填字段和日期格式
+func validateMovieCreate(movieCreate *models.MovieCreate) error {
+	// 类型可以通过genre或genres提供
+	if movieCreate.Title == "" || movieCreate.ReleaseDate == "" || (movieCreate.Genre == "" && len(movieCreate.Genres) == 0) {
+		return fmt.Errorf("Title, release date and genre are required")
+	}
+
+	// 验证ReleaseDate格式是否正确（YYYY-MM-DD）
+	if _, err := time.Parse("2006-01-02", movieCreate.ReleaseDate); err != nil {
+		return fmt.Errorf("Release date must be in YYYY-MM-DD format")
+	}
+
+	return nil
+}
+
+// resolveGenres 校验并解析创建请求中的类型，genre作为主类型排在genres之前，
+// 返回去重后的标准类型名称列表
+func (s *movieService) resolveGenres(movieCreate *models.MovieCreate) ([]string, error) {
+	inputs := make([]string, 0, len(movieCreate.Genres)+1)
+	if strings.TrimSpace(movieCreate.Genre) != "" {
+		inputs = append(inputs, movieCreate.Genre)
+	}
+	inputs = append(inputs, movieCreate.Genres...)
+
+	var genres []string
+	seen := make(map[string]bool)
+	for _, input := range inputs {
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		genre, err := s.genreRepo.Resolve(input)
+		if err != nil {
+			return nil, err
+		}
+		if genre == nil {
+			return nil, fmt.Errorf("unknown genre '%s'", input)
+		}
+
+		if !seen[genre.Name] {
+			seen[genre.Name] = true
+			genres = append(genres, genre.Name)
+		}
+	}
+
+	if len(genres) == 0 {
+		return nil, fmt.Errorf("genre is required")
+	}
+	return genres, nil
+}
+
+// LocalizeMovies 根据客户端语言偏好为电影填充本地化标题
+func (s *movieService) LocalizeMovies(movies []models.Movie, languages []string) error {
+	if len(movies) == 0 || len(languages) == 0 {
+		return nil
+	}
+
+	movieIDs := make([]string, 0, len(movies))
+	for _, movie := range movies {
+		movieIDs = append(movieIDs, movie.ID)
+	}
+
+	aliasesByMovie, err := s.aliasRepo.ListByMovies(movieIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range movies {
+		aliases := aliasesByMovie[movies[i].ID]
+		if len(aliases) == 0 {
+			continue
+		}
+
+		available := make([]string, 0, len(aliases))
+		for _, alias := range aliases {
+			available = append(available, alias.Language)
+		}
+
+		lang := matchLanguage(languages, available)
+		if lang == "" {
+			continue
+		}
+		for _, alias := range aliases {
+			if alias.Language == lang {
+				title, language := alias.Title, alias.Language
+				movies[i].LocalizedTitle = &title
+				movies[i].TitleLanguage = &language
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListAliases 获取电影的所有别名
+func (s *movieService) ListAliases(title string) ([]models.MovieAlias, error) {
+	movie, err := s.GetMovieByTitle(title)
+	if err != nil {
+		return nil, err
+	}
+	return s.aliasRepo.ListByMovie(movie.ID)
+}
+
+// AddAlias 为电影添加别名/本地化标题
+func (s *movieService) AddAlias(title string, aliasCreate *models.MovieAliasCreate) (*models.MovieAlias, error) {
+	aliasTitle := strings.TrimSpace(aliasCreate.Title)
+	if aliasTitle == "" {
+		return nil, fmt.Errorf("alias title is required")
+	}
+
+	language, err := normalizeLanguageTag(aliasCreate.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err := s.GetMovieByTitle(title)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.aliasRepo.ListByMovie(movie.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range existing {
+		if alias.Language == language && alias.Title == aliasTitle {
+			return nil, fmt.Errorf("alias '%s' (%s) already exists", aliasTitle, language)
+		}
+	}
+
+	alias := &models.MovieAlias{
+		MovieID:  movie.ID,
+		Title:    aliasTitle,
+		Language: language,
+	}
+	if err := s.aliasRepo.Create(alias); err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// DeleteAlias 删除电影的别名
+func (s *movieService) DeleteAlias(title string, aliasID int64) error {
+	movie, err := s.GetMovieByTitle(title)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.aliasRepo.Delete(movie.ID, aliasID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("alias not found")
+	}
+	return nil
 }
 
 // generateMovieID 生成电影ID
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+	"strings"
+	"time"
+)
+
+// PersonService 演职人员服务接口
+type PersonService interface {
+	CreatePerson(personCreate *models.PersonCreate) (*models.Person, error)
+	GetPerson(id string) (*models.Person, error)
+	ListPeople(q string, limit int, cursor string) (*models.PersonPage, error)
+	UpdatePerson(id string, personUpdate *models.PersonCreate) (*models.Person, error)
+	DeletePerson(id string) error
+	GetFilmography(id string) (*models.Filmography, error)
+	AddCredit(movieTitle string, creditCreate *models.CreditCreate) (*models.Credit, error)
+	ListCredits(movieTitle string) ([]models.Credit, error)
+	DeleteCredit(movieTitle string, creditID int64) error
+}
+
+// personService 演职人员服务实现
+type personService struct {
+	personRepo repository.PersonRepository
+	creditRepo repository.CreditRepository
+	movieRepo  repository.MovieRepository
+}
+
+// NewPersonService 创建演职人员服务实例
+func NewPersonService(personRepo repository.PersonRepository, creditRepo repository.CreditRepository, movieRepo repository.MovieRepository) PersonService {
+	return &personService{
+		personRepo: personRepo,
+		creditRepo: creditRepo,
+		movieRepo:  movieRepo,
+	}
+}
+
+// CreatePerson 创建演职人员
+func (s *personService) CreatePerson(personCreate *models.PersonCreate) (*models.Person, error) {
+	if err := validatePerson(personCreate); err != nil {
+		return nil, err
+	}
+
+	person := &models.Person{
+		ID:        generatePersonID(personCreate.Name),
+		Name:      strings.TrimSpace(personCreate.Name),
+		BirthDate: personCreate.BirthDate,
+		Biography: personCreate.Biography,
+	}
+
+	if err := s.personRepo.Create(person); err != nil {
+		return nil, err
+	}
+
+	return person, nil
+}
+
+// GetPerson 根据ID获取演职人员
+func (s *personService) GetPerson(id string) (*models.Person, error) {
+	person, err := s.personRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, fmt.Errorf("person not found")
+	}
+	return person, nil
+}
+
+// ListPeople 列出演职人员
+func (s *personService) ListPeople(q string, limit int, cursor string) (*models.PersonPage, error) {
+	return s.personRepo.List(q, limit, cursor)
+}
+
+// UpdatePerson 更新演职人员信息
+func (s *personService) UpdatePerson(id string, personUpdate *models.PersonCreate) (*models.Person, error) {
+	if err := validatePerson(personUpdate); err != nil {
+		return nil, err
+	}
+
+	person := &models.Person{
+		ID:        id,
+		Name:      strings.TrimSpace(personUpdate.Name),
+		BirthDate: personUpdate.BirthDate,
+		Biography: personUpdate.Biography,
+	}
+
+	if err := s.personRepo.Update(person); err != nil {
+		return nil, err
+	}
+
+	return person, nil
+}
+
+// DeletePerson 删除演职人员
+func (s *personService) DeletePerson(id string) error {
+	deleted, err := s.personRepo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("person not found")
+	}
+	return nil
+}
+
+// GetFilmography 获取演职人员的作品年表
+func (s *personService) GetFilmography(id string) (*models.Filmography, error) {
+	person, err := s.GetPerson(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.creditRepo.ListByPerson(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Filmography{Person: *person, Items: entries}, nil
+}
+
+// AddCredit 为电影添加署名
+func (s *personService) AddCredit(movieTitle string, creditCreate *models.CreditCreate) (*models.Credit, error) {
+	role := strings.ToLower(strings.TrimSpace(creditCreate.Role))
+	if !isValidCreditRole(role) {
+		return nil, fmt.Errorf("invalid role '%s', must be one of: %s", creditCreate.Role, strings.Join(models.CreditRoles, ", "))
+	}
+	if creditCreate.BillingOrder < 0 {
+		return nil, fmt.Errorf("invalid billing order: must not be negative")
+	}
+
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	person, err := s.GetPerson(creditCreate.PersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	credit := &models.Credit{
+		MovieID:      movie.ID,
+		MovieTitle:   movie.Title,
+		PersonID:     person.ID,
+		PersonName:   person.Name,
+		Role:         role,
+		Character:    strings.TrimSpace(creditCreate.Character),
+		BillingOrder: creditCreate.BillingOrder,
+	}
+
+	if err := s.creditRepo.Create(credit); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("credit already exists")
+		}
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+// ListCredits 获取电影的演职员表
+func (s *personService) ListCredits(movieTitle string) ([]models.Credit, error) {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	return s.creditRepo.ListByMovie(movie.ID)
+}
+
+// DeleteCredit 删除电影的署名
+func (s *personService) DeleteCredit(movieTitle string, creditID int64) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.creditRepo.Delete(movie.ID, creditID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("credit not found")
+	}
+	return nil
+}
+
+// getMovie 根据标题（或别名）获取电影
+func (s *personService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}
+
+// validatePerson 验证演职人员请求
+func validatePerson(personCreate *models.PersonCreate) error {
+	if strings.TrimSpace(personCreate.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if personCreate.BirthDate != nil {
+		if _, err := time.Parse("2006-01-02", *personCreate.BirthDate); err != nil {
+			return fmt.Errorf("birth date must be in YYYY-MM-DD format")
+		}
+	}
+	return nil
+}
+
+// isValidCreditRole 判断角色是否合法
+func isValidCreditRole(role string) bool {
+	for _, r := range models.CreditRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// generatePersonID 生成演职人员ID
+func generatePersonID(name string) string {
+	// 按字符而非字节截断，避免截断多字节字符（如中文姓名）
+	cleanName := []rune(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_"))
+	return fmt.Sprintf("p_%s_%d", string(cleanName[:min(len(cleanName), 10)]), time.Now().UnixNano()/1000000)
+}
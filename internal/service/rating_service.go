@@ -4,25 +4,52 @@ import (
 	"fmt"
 	"movie-rating-api/internal/models"
 	"movie-rating-api/internal/repository"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 // RatingService 评分服务接口
 type RatingService interface {
 	SubmitRating(movieTitle string, raterID string, submit *models.RatingSubmit) (*models.RatingResult, error)
-	GetMovieRatings(movieTitle string) (*models.RatingAggregate, error)
+	GetMovieRatings(movieTitle string, excludeFlagged bool) (*models.RatingAggregate, error)
+	GetRaterRating(movieTitle string, raterID string) (*models.Rating, error)
+	ListRaterRatings(raterID, sortBy, order string, limit int, cursor string) (*models.RaterRatingPage, error)
+	GetRaterStats(raterID string) (*models.RaterStats, error)
+	WithdrawRating(movieTitle string, raterID string) (*models.RatingAggregate, error)
+	GetMovieRatingsAsOf(movieTitle string, asOf time.Time) (*models.RatingAggregate, error)
+	ListRatingEvents(movieTitle string, limit int, cursor string) (*models.RatingEventPage, error)
 }
 
 // ratingService 评分服务实现
 type ratingService struct {
-	ratingRepo repository.RatingRepository
-	movieRepo  repository.MovieRepository
+	ratingRepo   repository.RatingRepository
+	eventRepo    repository.RatingEventRepository
+	movieRepo    repository.MovieRepository
+	fraudService FraudService
+	listRepo     repository.ListRepository
+	moderation   ReviewModerationService
+	rounding     string
+
+	// analyzing 正在做刷分检测的电影，值为检测期间是否又有新的提交
+	analyzeMu sync.Mutex
+	analyzing map[string]bool
 }
 
-// NewRatingService 创建评分服务实例
-func NewRatingService(ratingRepo repository.RatingRepository, movieRepo repository.MovieRepository) RatingService {
+// NewRatingService 创建评分服务实例，fraudService为nil时不做刷分检测，listRepo为nil时
+// 评分不会标记待看列表；评分附带的comment保存为评论并交给moderation审核；
+// rounding为返回的平均评分使用的舍入方式
+func NewRatingService(ratingRepo repository.RatingRepository, eventRepo repository.RatingEventRepository, movieRepo repository.MovieRepository, fraudService FraudService, listRepo repository.ListRepository, moderation ReviewModerationService, rounding string) RatingService {
 	return &ratingService{
-		ratingRepo: ratingRepo,
-		movieRepo:  movieRepo,
+		ratingRepo:   ratingRepo,
+		eventRepo:    eventRepo,
+		movieRepo:    movieRepo,
+		fraudService: fraudService,
+		listRepo:     listRepo,
+		moderation:   moderation,
+		rounding:     rounding,
+		analyzing:    make(map[string]bool),
 	}
 }
 
@@ -32,6 +59,10 @@ func (s *ratingService) SubmitRating(movieTitle string, raterID string, submit *
 	if submit.Score < 0.5 || submit.Score > 5 {
 		return nil, fmt.Errorf("rating must be between 0.5 and 5")
 	}
+	comment := strings.TrimSpace(submit.Comment)
+	if utf8.RuneCountInString(comment) > models.MaxReviewLength {
+		return nil, fmt.Errorf("comment must be at most %d characters", models.MaxReviewLength)
+	}
 
 	// 检查电影是否存在
 	movie, err := s.movieRepo.GetByTitle(movieTitle)
@@ -42,6 +73,9 @@ func (s *ratingService) SubmitRating(movieTitle string, raterID string, submit *
 		return nil, fmt.Errorf("movie not found")
 	}
 
+	// 标题可能是别名，统一使用正式标题存储评分
+	movieTitle = movie.Title
+
 	// 创建评分实例
 	rating := &models.Rating{
 		MovieTitle: movieTitle,
@@ -61,11 +95,69 @@ func (s *ratingService) SubmitRating(movieTitle string, raterID string, submit *
 		Rating:     submit.Score,
 	}
 
+	// 附带评论时保存评论并审核；不带评论的评分保留之前的评论。
+	// 评分已提交，评论保存失败时仍返回评分结果，并在reviewError中说明评论未保存
+	if comment != "" {
+		review := &models.Review{MovieTitle: movieTitle, RaterID: raterID, Body: comment}
+		if err := s.moderation.SubmitReview(review); err != nil {
+			fmt.Printf("Error saving review with rating: %v\n", err)
+			result.ReviewError = "Failed to save the comment, the rating was saved"
+		} else {
+			result.ReviewID = &review.ID
+			result.ReviewStatus = review.Status
+		}
+	}
+
+	// 评分即视为已看过，标记待看列表中的这部电影；评分已保存，标记失败只记录日志
+	if s.listRepo != nil {
+		if _, err := s.listRepo.MarkWatched(raterID, movieTitle); err != nil {
+			fmt.Printf("Error marking watchlist entry as watched: %v\n", err)
+		}
+	}
+
+	// 异步检测刷分，不影响评分提交的响应时间
+	if s.fraudService != nil {
+		s.analyzeAsync(movieTitle)
+	}
+
 	return result, nil
 }
 
-// GetMovieRatings 获取电影的聚合评分
-func (s *ratingService) GetMovieRatings(movieTitle string) (*models.RatingAggregate, error) {
+// analyzeAsync 在后台检测电影的刷分。每部电影同时只有一个检测在运行，
+// 检测期间的新提交合并为结束后的一次重新检测，突发提交不会堆积goroutine
+func (s *ratingService) analyzeAsync(movieTitle string) {
+	s.analyzeMu.Lock()
+	if _, running := s.analyzing[movieTitle]; running {
+		s.analyzing[movieTitle] = true
+		s.analyzeMu.Unlock()
+		return
+	}
+	s.analyzing[movieTitle] = false
+	s.analyzeMu.Unlock()
+
+	go func() {
+		for {
+			flagged, err := s.fraudService.Analyze(movieTitle)
+			if err != nil {
+				fmt.Printf("Error analyzing ratings for fraud: %v\n", err)
+			} else if flagged > 0 {
+				fmt.Printf("Flagged %d suspicious ratings on %q\n", flagged, movieTitle)
+			}
+
+			s.analyzeMu.Lock()
+			if !s.analyzing[movieTitle] {
+				delete(s.analyzing, movieTitle)
+				s.analyzeMu.Unlock()
+				return
+			}
+			s.analyzing[movieTitle] = false
+			s.analyzeMu.Unlock()
+		}
+	}()
+}
+
+// GetMovieRatings 获取电影的聚合评分，excludeFlagged为true时排除待审核的可疑评分
+func (s *ratingService) GetMovieRatings(movieTitle string, excludeFlagged bool) (*models.RatingAggregate, error) {
 	// 检查电影是否存在
 	movie, err := s.movieRepo.GetByTitle(movieTitle)
 	if err != nil {
@@ -75,11 +167,107 @@ func (s *ratingService) GetMovieRatings(movieTitle string) (*models.RatingAggreg
 		return nil, fmt.Errorf("movie not found")
 	}
 
-	// 获取聚合评分
-	aggregate, err := s.ratingRepo.GetAggregateByMovie(movieTitle)
+	// 获取聚合评分（使用正式标题，以便别名也能查到评分）
+	aggregate, err := s.ratingRepo.GetAggregateByMovie(movie.Title, excludeFlagged)
 	if err != nil {
 		return nil, err
 	}
+	aggregate.Round(s.rounding)
 
 	return aggregate, nil
 }
+
+// GetRaterRating 获取评分者对电影的评分，未评分时返回nil
+func (s *ratingService) GetRaterRating(movieTitle string, raterID string) (*models.Rating, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil || movie == nil {
+		return nil, err
+	}
+
+	return s.ratingRepo.GetByMovieAndRater(movie.Title, raterID)
+}
+
+// ListRaterRatings 分页获取评分者的评分历史，默认按评分时间倒序
+func (s *ratingService) ListRaterRatings(raterID, sortBy, order string, limit int, cursor string) (*models.RaterRatingPage, error) {
+	if sortBy == "" {
+		sortBy = "date"
+	}
+	if order == "" {
+		order = "desc"
+	}
+
+	return s.ratingRepo.ListByRater(raterID, sortBy, order, limit, cursor)
+}
+
+// GetRaterStats 获取评分者的评分统计
+func (s *ratingService) GetRaterStats(raterID string) (*models.RaterStats, error) {
+	stats, err := s.ratingRepo.GetRaterStats(raterID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Average = models.RoundAverage(stats.Average, s.rounding)
+	for i := range stats.Genres {
+		stats.Genres[i].Average = models.RoundAverage(stats.Genres[i].Average, s.rounding)
+	}
+	return stats, nil
+}
+
+// WithdrawRating 撤回评分者对电影的评分，返回撤回后的聚合评分
+func (s *ratingService) WithdrawRating(movieTitle string, raterID string) (*models.RatingAggregate, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+
+	deleted, err := s.ratingRepo.Delete(movie.Title, raterID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, fmt.Errorf("rating not found")
+	}
+
+	aggregate, err := s.ratingRepo.GetAggregateByMovie(movie.Title, false)
+	if err != nil {
+		return nil, err
+	}
+	aggregate.Round(s.rounding)
+
+	return aggregate, nil
+}
+
+// GetMovieRatingsAsOf 根据评分事件计算电影在指定时间点的聚合评分
+func (s *ratingService) GetMovieRatingsAsOf(movieTitle string, asOf time.Time) (*models.RatingAggregate, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+
+	aggregate, err := s.eventRepo.GetAggregateAsOf(movie.Title, asOf)
+	if err != nil {
+		return nil, err
+	}
+	aggregate.Round(s.rounding)
+
+	return aggregate, nil
+}
+
+// ListRatingEvents 按时间顺序分页获取电影的评分变更记录
+func (s *ratingService) ListRatingEvents(movieTitle string, limit int, cursor string) (*models.RatingEventPage, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+
+	return s.eventRepo.ListByMovie(movie.Title, limit, cursor)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// RatingStatsService 物化评分聚合对账服务接口
+type RatingStatsService interface {
+	Reconcile(repair bool) (*models.RatingStatsReport, error)
+}
+
+// ratingStatsService 物化评分聚合对账服务实现
+type ratingStatsService struct {
+	statsRepo repository.RatingStatsRepository
+}
+
+// NewRatingStatsService 创建物化评分聚合对账服务实例
+func NewRatingStatsService(statsRepo repository.RatingStatsRepository) RatingStatsService {
+	return &ratingStatsService{statsRepo: statsRepo}
+}
+
+// Reconcile 从评分表重新计算全部聚合并与物化表比较，报告不一致的电影；
+// repair为true时用重新计算的结果覆盖物化表
+func (s *ratingStatsService) Reconcile(repair bool) (*models.RatingStatsReport, error) {
+	drifts, err := s.statsRepo.FindDrift()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RatingStatsReport{Drifted: len(drifts), Items: drifts}
+	if !repair || len(drifts) == 0 {
+		return report, nil
+	}
+
+	// 逐部电影重建：重建时重新读取评分表，期间发生的评分变更不会被旧的比较结果覆盖
+	for _, drift := range drifts {
+		if err := s.statsRepo.Rebuild(drift.MovieTitle); err != nil {
+			return nil, err
+		}
+	}
+	report.Repaired = true
+
+	return report, nil
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// RecommendationConfig 矩阵分解模型的训练参数
+type RecommendationConfig struct {
+	Factors        int
+	Epochs         int
+	LearningRate   float64
+	Regularization float64
+	Seed           int64
+	// LikedThreshold 评分不低于该值的电影才会作为推荐理由
+	LikedThreshold float64
+}
+
+// DefaultRecommendationConfig 默认训练参数
+func DefaultRecommendationConfig() RecommendationConfig {
+	return RecommendationConfig{
+		Factors:        10,
+		Epochs:         40,
+		LearningRate:   0.01,
+		Regularization: 0.05,
+		Seed:           1,
+		LikedThreshold: 4,
+	}
+}
+
+// RecommendationService 个性化推荐服务接口
+type RecommendationService interface {
+	Train() (int, error)
+	Recommend(raterID string, query map[string]interface{}, limit int) (*models.RecommendationList, error)
+	Evaluate(holdout float64, seed int64) (*models.RecommendationEvaluation, error)
+}
+
+// recommendationService 个性化推荐服务实现
+type recommendationService struct {
+	recommendationRepo repository.RecommendationRepository
+	similarityRepo     repository.SimilarityRepository
+	config             RecommendationConfig
+
+	mu    sync.RWMutex
+	model *factorModel
+	// trainMu 串行化训练：冷启动时的并发请求只训练一次，其余请求等待训练结果
+	trainMu sync.Mutex
+}
+
+// NewRecommendationService 创建个性化推荐服务实例，训练数据与相似度计算使用同一份评分（排除已确认的刷分评分）
+func NewRecommendationService(recommendationRepo repository.RecommendationRepository, similarityRepo repository.SimilarityRepository, config RecommendationConfig) RecommendationService {
+	return &recommendationService{
+		recommendationRepo: recommendationRepo,
+		similarityRepo:     similarityRepo,
+		config:             config,
+	}
+}
+
+// factorModel 带偏置项的矩阵分解模型：预测分 = 全局平均 + 评分者偏置 + 电影偏置 + 评分者向量·电影向量
+type factorModel struct {
+	globalMean  float64
+	raterBias   map[string]float64
+	movieBias   map[string]float64
+	raterVector map[string][]float64
+	movieVector map[string][]float64
+	trainedAt   time.Time
+}
+
+// trainFactorModel 用随机梯度下降训练矩阵分解模型，相同的种子得到相同的模型
+func trainFactorModel(ratings []models.Rating, config RecommendationConfig) *factorModel {
+	model := &factorModel{
+		raterBias:   make(map[string]float64),
+		movieBias:   make(map[string]float64),
+		raterVector: make(map[string][]float64),
+		movieVector: make(map[string][]float64),
+		trainedAt:   time.Now().UTC(),
+	}
+	if len(ratings) == 0 {
+		return model
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	newVector := func() []float64 {
+		vector := make([]float64, config.Factors)
+		for i := range vector {
+			vector[i] = rng.NormFloat64() * 0.1
+		}
+		return vector
+	}
+
+	sum := 0.0
+	for _, rating := range ratings {
+		sum += rating.Rating
+		if _, ok := model.raterVector[rating.RaterID]; !ok {
+			model.raterVector[rating.RaterID] = newVector()
+		}
+		if _, ok := model.movieVector[rating.MovieTitle]; !ok {
+			model.movieVector[rating.MovieTitle] = newVector()
+		}
+	}
+	model.globalMean = sum / float64(len(ratings))
+
+	order := make([]int, len(ratings))
+	for i := range order {
+		order[i] = i
+	}
+
+	lr, reg := config.LearningRate, config.Regularization
+	for epoch := 0; epoch < config.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for _, index := range order {
+			rating := ratings[index]
+			p := model.raterVector[rating.RaterID]
+			q := model.movieVector[rating.MovieTitle]
+
+			err := rating.Rating - model.rawPredict(rating.RaterID, rating.MovieTitle)
+			model.raterBias[rating.RaterID] += lr * (err - reg*model.raterBias[rating.RaterID])
+			model.movieBias[rating.MovieTitle] += lr * (err - reg*model.movieBias[rating.MovieTitle])
+			for f := range p {
+				pf, qf := p[f], q[f]
+				p[f] += lr * (err*qf - reg*pf)
+				q[f] += lr * (err*pf - reg*qf)
+			}
+		}
+	}
+
+	return model
+}
+
+// rawPredict 未截断的预测分，评分者或电影不在训练集中时对应项为0
+func (m *factorModel) rawPredict(raterID, movieTitle string) float64 {
+	prediction := m.globalMean + m.raterBias[raterID] + m.movieBias[movieTitle]
+	p, q := m.raterVector[raterID], m.movieVector[movieTitle]
+	if p != nil && q != nil {
+		for f := range p {
+			prediction += p[f] * q[f]
+		}
+	}
+	return prediction
+}
+
+// predict 预测分，截断到合法评分范围
+func (m *factorModel) predict(raterID, movieTitle string) float64 {
+	return math.Max(0.5, math.Min(5, m.rawPredict(raterID, movieTitle)))
+}
+
+// movieSimilarity 两部电影向量的余弦相似度
+func (m *factorModel) movieSimilarity(a, b string) float64 {
+	va, vb := m.movieVector[a], m.movieVector[b]
+	if va == nil || vb == nil {
+		return 0
+	}
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for f := range va {
+		dot += va[f] * vb[f]
+		normA += va[f] * va[f]
+		normB += vb[f] * vb[f]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// Train 用当前全部评分重新训练模型，返回训练使用的评分数
+func (s *recommendationService) Train() (int, error) {
+	s.trainMu.Lock()
+	defer s.trainMu.Unlock()
+	return s.train()
+}
+
+// train 训练模型并替换当前模型，调用方须持有trainMu
+func (s *recommendationService) train() (int, error) {
+	ratings, err := s.similarityRepo.ListRatings()
+	if err != nil {
+		return 0, err
+	}
+
+	model := trainFactorModel(ratings, s.config)
+
+	s.mu.Lock()
+	s.model = model
+	s.mu.Unlock()
+
+	return len(ratings), nil
+}
+
+// currentModel 获取当前模型，尚未训练时先训练一次。并发的冷启动请求在trainMu上排队，
+// 拿到锁后重新检查，只有第一个请求真正训练；训练失败时下一个请求重试
+func (s *recommendationService) currentModel() (*factorModel, error) {
+	if model := s.loadModel(); model != nil {
+		return model, nil
+	}
+
+	s.trainMu.Lock()
+	defer s.trainMu.Unlock()
+	if model := s.loadModel(); model != nil {
+		return model, nil
+	}
+
+	if _, err := s.train(); err != nil {
+		return nil, err
+	}
+	return s.loadModel(), nil
+}
+
+// loadModel 读取当前模型，尚未训练时返回nil
+func (s *recommendationService) loadModel() *factorModel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.model
+}
+
+// Recommend 按预测分从高到低推荐评分者尚未评分的电影。
+// 只推荐模型见过的电影（至少有一条评分），新评分者的推荐退化为按电影偏置排序的热门高分电影
+func (s *recommendationService) Recommend(raterID string, query map[string]interface{}, limit int) (*models.RecommendationList, error) {
+	model, err := s.currentModel()
+	if err != nil {
+		return nil, err
+	}
+
+	rated, err := s.recommendationRepo.ListRaterRatings(raterID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.recommendationRepo.ListCandidates(raterID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 推荐理由只从评分者打过高分的电影中选择
+	liked := []string{}
+	for _, rating := range rated {
+		if rating.Rating >= s.config.LikedThreshold {
+			liked = append(liked, rating.MovieTitle)
+		}
+	}
+	sort.Strings(liked)
+
+	items := []models.Recommendation{}
+	for _, candidate := range candidates {
+		if model.movieVector[candidate.Title] == nil {
+			continue
+		}
+		items = append(items, models.Recommendation{
+			Title:          candidate.Title,
+			ReleaseDate:    candidate.ReleaseDate,
+			Genres:         candidate.Genres,
+			PredictedScore: math.Round(model.predict(raterID, candidate.Title)*100) / 100,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].PredictedScore != items[j].PredictedScore {
+			return items[i].PredictedScore > items[j].PredictedScore
+		}
+		return items[i].Title < items[j].Title
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	for i := range items {
+		explainRecommendation(model, &items[i], liked, len(rated) > 0)
+	}
+
+	return &models.RecommendationList{RaterID: raterID, Items: items, ModelTrainedAt: model.trainedAt}, nil
+}
+
+// explainRecommendation 选出评分者喜欢的电影中与推荐电影最接近的一部作为推荐理由
+func explainRecommendation(model *factorModel, item *models.Recommendation, liked []string, hasHistory bool) {
+	best, bestScore := "", 0.0
+	for _, title := range liked {
+		if score := model.movieSimilarity(item.Title, title); score > bestScore {
+			best, bestScore = title, score
+		}
+	}
+
+	if best == "" {
+		if hasHistory {
+			item.Explanation = "Predicted from your rating history"
+		} else {
+			item.Explanation = "Highly rated by other raters"
+		}
+		return
+	}
+	item.BecauseYouRated = &best
+	item.Explanation = fmt.Sprintf("Because you rated %s highly", best)
+}
+
+// Evaluate 随机留出部分评分作为测试集，用其余评分训练模型并报告测试集上的RMSE
+func (s *recommendationService) Evaluate(holdout float64, seed int64) (*models.RecommendationEvaluation, error) {
+	if holdout <= 0 || holdout >= 1 {
+		return nil, fmt.Errorf("invalid holdout: must be between 0 and 1")
+	}
+
+	ratings, err := s.similarityRepo.ListRatings()
+	if err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	var train, test []models.Rating
+	for _, rating := range ratings {
+		if rng.Float64() < holdout {
+			test = append(test, rating)
+		} else {
+			train = append(train, rating)
+		}
+	}
+	if len(train) == 0 || len(test) == 0 {
+		return nil, fmt.Errorf("not enough ratings to evaluate: %d ratings", len(ratings))
+	}
+
+	config := s.config
+	config.Seed = seed
+	model := trainFactorModel(train, config)
+
+	squaredError, baselineSquaredError := 0.0, 0.0
+	for _, rating := range test {
+		diff := rating.Rating - model.predict(rating.RaterID, rating.MovieTitle)
+		squaredError += diff * diff
+		baselineDiff := rating.Rating - model.globalMean
+		baselineSquaredError += baselineDiff * baselineDiff
+	}
+
+	return &models.RecommendationEvaluation{
+		TrainRatings: len(train),
+		TestRatings:  len(test),
+		Holdout:      holdout,
+		Seed:         seed,
+		Factors:      config.Factors,
+		Epochs:       config.Epochs,
+		RMSE:         math.Sqrt(squaredError / float64(len(test))),
+		BaselineRMSE: math.Sqrt(baselineSquaredError / float64(len(test))),
+	}, nil
+}
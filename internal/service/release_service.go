@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// 上映日历的默认时间窗口和最大时间跨度
+const (
+	defaultCalendarDays = 30
+	maxCalendarDays     = 366
+)
+
+// ReleaseService 地区上映记录服务接口
+type ReleaseService interface {
+	ListReleases(movieTitle string) ([]models.MovieRelease, error)
+	SetRelease(movieTitle, region, channel string, releaseUpsert *models.MovieReleaseUpsert) (*models.MovieRelease, bool, error)
+	DeleteRelease(movieTitle, region, channel string) error
+	GetCalendar(from, to, region, channel string, limit int, cursor string) (*models.CalendarPage, error)
+}
+
+// releaseService 地区上映记录服务实现
+type releaseService struct {
+	releaseRepo repository.ReleaseRepository
+	movieRepo   repository.MovieRepository
+}
+
+// NewReleaseService 创建地区上映记录服务实例
+func NewReleaseService(releaseRepo repository.ReleaseRepository, movieRepo repository.MovieRepository) ReleaseService {
+	return &releaseService{
+		releaseRepo: releaseRepo,
+		movieRepo:   movieRepo,
+	}
+}
+
+// ListReleases 获取电影在各地区各渠道的上映记录
+func (s *releaseService) ListReleases(movieTitle string) ([]models.MovieRelease, error) {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.releaseRepo.ListByMovie(movie.ID)
+}
+
+// SetRelease 设置电影在某地区某渠道的上映日期，返回上映记录以及是否新建
+func (s *releaseService) SetRelease(movieTitle, region, channel string, releaseUpsert *models.MovieReleaseUpsert) (*models.MovieRelease, bool, error) {
+	region, err := normalizeRegion(region)
+	if err != nil {
+		return nil, false, err
+	}
+	if !models.ValidReleaseChannel(channel) {
+		return nil, false, fmt.Errorf("invalid channel '%s', must be one of: %s", channel, strings.Join(models.ReleaseChannels, ", "))
+	}
+	if _, err := time.Parse("2006-01-02", releaseUpsert.ReleaseDate); err != nil {
+		return nil, false, fmt.Errorf("invalid releaseDate format, must be YYYY-MM-DD")
+	}
+
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	release := &models.MovieRelease{
+		Region:      region,
+		Channel:     channel,
+		ReleaseDate: releaseUpsert.ReleaseDate,
+		Note:        releaseUpsert.Note,
+	}
+	created, err := s.releaseRepo.Upsert(movie.ID, release)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return release, created, nil
+}
+
+// DeleteRelease 删除电影在某地区某渠道的上映记录
+func (s *releaseService) DeleteRelease(movieTitle, region, channel string) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.releaseRepo.Delete(movie.ID, strings.ToUpper(region), channel)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("release not found")
+	}
+	return nil
+}
+
+// GetCalendar 分页获取日期范围内的上映记录。from默认为今天，to默认为from之后30天，
+// 范围最多366天；region和channel为空时包含所有地区和渠道
+func (s *releaseService) GetCalendar(from, to, region, channel string, limit int, cursor string) (*models.CalendarPage, error) {
+	fromDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date, must be YYYY-MM-DD")
+		}
+		fromDate = parsed
+	}
+
+	toDate := fromDate.AddDate(0, 0, defaultCalendarDays)
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date, must be YYYY-MM-DD")
+		}
+		toDate = parsed
+	}
+
+	if toDate.Before(fromDate) {
+		return nil, fmt.Errorf("invalid date range: to must not be before from")
+	}
+	if toDate.Sub(fromDate) > maxCalendarDays*24*time.Hour {
+		return nil, fmt.Errorf("invalid date range: must span at most %d days", maxCalendarDays)
+	}
+
+	if region != "" {
+		var err error
+		if region, err = normalizeRegion(region); err != nil {
+			return nil, err
+		}
+	}
+	if channel != "" && !models.ValidReleaseChannel(channel) {
+		return nil, fmt.Errorf("invalid channel '%s', must be one of: %s", channel, strings.Join(models.ReleaseChannels, ", "))
+	}
+
+	return s.releaseRepo.ListCalendar(fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"), region, channel, limit, cursor)
+}
+
+// getMovie 根据标题或别名获取电影
+func (s *releaseService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}
+
+// normalizeRegion 将地区代码转为大写并校验格式
+func normalizeRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if !models.ValidRegion(region) {
+		return "", fmt.Errorf("invalid region '%s', must be a two-letter ISO 3166-1 code", region)
+	}
+	return region, nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// AutoModeratorName 自动审核决定记录的审核人
+const AutoModeratorName = "auto-moderator"
+
+// AutoModerationConfig 自动审核规则
+type AutoModerationConfig struct {
+	// BlockedWords 包含任一词语（整词匹配，不区分大小写）的评论直接拒绝
+	BlockedWords []string
+	// MaxLinks 链接数超过该值的评论留待人工审核
+	MaxLinks int
+	// MaxUppercaseRatio 字母不少于MinLettersForCaps个、大写字母比例超过该值的评论留待人工审核
+	MaxUppercaseRatio float64
+	MinLettersForCaps int
+	// MaxRepeatedChars 同一字符连续出现超过该次数的评论留待人工审核
+	MaxRepeatedChars int
+	// AutoApprove 通过全部规则的评论是否自动公开，否则全部留待人工审核
+	AutoApprove bool
+	// ReportThreshold 已公开评论的未处理举报达到该数量时撤回到pending重新审核
+	ReportThreshold int
+}
+
+// DefaultAutoModerationConfig 默认自动审核规则
+func DefaultAutoModerationConfig() AutoModerationConfig {
+	return AutoModerationConfig{
+		MaxLinks:          2,
+		MaxUppercaseRatio: 0.7,
+		MinLettersForCaps: 20,
+		MaxRepeatedChars:  9,
+		AutoApprove:       true,
+		ReportThreshold:   3,
+	}
+}
+
+// LoadWordList 读取词表文件，每行一个词语，忽略空行和#开头的注释
+func LoadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// ReviewModerationService 评论审核服务接口
+type ReviewModerationService interface {
+	SubmitReview(review *models.Review) error
+	ListQueue(status string, limit int, cursor string) (*models.ModerationQueuePage, error)
+	Decide(reviewID int64, moderator string, decision *models.ReviewModerationDecision) (*models.Review, error)
+	ListHistory(reviewID int64) ([]models.ReviewModerationEvent, error)
+	ReportReview(reviewID int64, reporterID string, reportCreate *models.ReviewReportCreate) (*models.ReviewReport, error)
+}
+
+// reviewModerationService 评论审核服务实现
+type reviewModerationService struct {
+	reviewRepo   repository.ReviewRepository
+	config       AutoModerationConfig
+	blockedWords []string
+}
+
+// NewReviewModerationService 创建评论审核服务实例
+func NewReviewModerationService(reviewRepo repository.ReviewRepository, config AutoModerationConfig) ReviewModerationService {
+	blockedWords := make([]string, 0, len(config.BlockedWords))
+	for _, word := range config.BlockedWords {
+		if normalized := normalizeModerationText(word); strings.TrimSpace(normalized) != "" {
+			blockedWords = append(blockedWords, normalized)
+		}
+	}
+
+	return &reviewModerationService{
+		reviewRepo:   reviewRepo,
+		config:       config,
+		blockedWords: blockedWords,
+	}
+}
+
+// SubmitReview 保存评论，新的或修改过的评论交给自动审核。被版主隐藏或拒绝的评论
+// 修改后留在人工审核队列，自动审核只能拒绝而不能重新批准，以免绕过版主的决定
+func (s *reviewModerationService) SubmitReview(review *models.Review) error {
+	changed, manualOnly, err := s.reviewRepo.Upsert(review)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	status, reason := s.autoModerate(review.Body)
+	if manualOnly && status != models.ReviewStatusRejected {
+		return nil
+	}
+	if _, err := s.reviewRepo.SetStatus(review.ID, models.ReviewStatusPending, status, AutoModeratorName, reason); err != nil {
+		return err
+	}
+	review.Status = status
+	return nil
+}
+
+// autoModerate 按规则判定评论的审核状态和原因，规则按严重程度依次检查
+func (s *reviewModerationService) autoModerate(body string) (string, string) {
+	normalized := normalizeModerationText(body)
+	for _, word := range s.blockedWords {
+		if strings.Contains(normalized, word) {
+			return models.ReviewStatusRejected, fmt.Sprintf("contains blocked word %q", strings.TrimSpace(word))
+		}
+	}
+
+	lower := strings.ToLower(body)
+	links := strings.Count(lower, "http://") + strings.Count(lower, "https://") + strings.Count(lower, "www.")
+	if links > s.config.MaxLinks {
+		return models.ReviewStatusPending, fmt.Sprintf("contains %d links", links)
+	}
+
+	letters, upper := 0, 0
+	for _, r := range body {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= s.config.MinLettersForCaps && float64(upper)/float64(letters) > s.config.MaxUppercaseRatio {
+		return models.ReviewStatusPending, "mostly uppercase"
+	}
+
+	run, last := 0, rune(0)
+	for _, r := range body {
+		if r == last {
+			run++
+		} else {
+			run, last = 1, r
+		}
+		if run > s.config.MaxRepeatedChars && !unicode.IsSpace(r) {
+			return models.ReviewStatusPending, fmt.Sprintf("repeated character %q", r)
+		}
+	}
+
+	if !s.config.AutoApprove {
+		return models.ReviewStatusPending, "held for manual review"
+	}
+	return models.ReviewStatusApproved, "passed automatic checks"
+}
+
+// normalizeModerationText 转为小写，非字母数字替换为空格，首尾补空格以便整词匹配
+func normalizeModerationText(text string) string {
+	var builder strings.Builder
+	builder.WriteByte(' ')
+	lastSpace := true
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			lastSpace = false
+		} else if !lastSpace {
+			builder.WriteByte(' ')
+			lastSpace = true
+		}
+	}
+	if !lastSpace {
+		builder.WriteByte(' ')
+	}
+	return builder.String()
+}
+
+// ListQueue 分页获取审核队列，默认为待审核的评论
+func (s *reviewModerationService) ListQueue(status string, limit int, cursor string) (*models.ModerationQueuePage, error) {
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+	if _, ok := models.ReviewTransitions[status]; !ok {
+		return nil, fmt.Errorf("invalid status: must be one of pending, approved, rejected, hidden")
+	}
+	return s.reviewRepo.ListQueue(status, limit, cursor)
+}
+
+// Decide 版主按状态机修改评论的审核状态，必须给出原因
+func (s *reviewModerationService) Decide(reviewID int64, moderator string, decision *models.ReviewModerationDecision) (*models.Review, error) {
+	reason := strings.TrimSpace(decision.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if _, ok := models.ReviewTransitions[decision.Status]; !ok {
+		return nil, fmt.Errorf("invalid status: must be one of pending, approved, rejected, hidden")
+	}
+
+	review, err := s.reviewRepo.GetByID(reviewID, "")
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, fmt.Errorf("review not found")
+	}
+	if !models.CanTransitionReview(review.Status, decision.Status) {
+		return nil, fmt.Errorf("invalid transition from %s to %s", review.Status, decision.Status)
+	}
+
+	updated, err := s.reviewRepo.SetStatus(reviewID, review.Status, decision.Status, moderator, reason)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("review status changed concurrently, reload and retry")
+	}
+
+	return s.reviewRepo.GetByID(reviewID, "")
+}
+
+// ListHistory 获取评论的审核记录
+func (s *reviewModerationService) ListHistory(reviewID int64) ([]models.ReviewModerationEvent, error) {
+	review, err := s.reviewRepo.GetByID(reviewID, "")
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, fmt.Errorf("review not found")
+	}
+	return s.reviewRepo.ListModerationEvents(reviewID)
+}
+
+// ReportReview 举报已公开的评论；未处理的举报达到阈值时评论撤回到pending等待版主处理
+func (s *reviewModerationService) ReportReview(reviewID int64, reporterID string, reportCreate *models.ReviewReportCreate) (*models.ReviewReport, error) {
+	if !isValidReportReason(reportCreate.Reason) {
+		return nil, fmt.Errorf("invalid reason '%s', must be one of: %s", reportCreate.Reason, strings.Join(models.ReviewReportReasons, ", "))
+	}
+
+	review, err := s.reviewRepo.GetByID(reviewID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil || review.Status != models.ReviewStatusApproved {
+		return nil, fmt.Errorf("review not found")
+	}
+	if review.RaterID == reporterID {
+		return nil, fmt.Errorf("cannot report your own review")
+	}
+
+	report := &models.ReviewReport{
+		ReviewID:   reviewID,
+		ReporterID: reporterID,
+		Reason:     reportCreate.Reason,
+		Details:    strings.TrimSpace(reportCreate.Details),
+	}
+	if err := s.reviewRepo.CreateReport(report); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("review already reported")
+		}
+		return nil, err
+	}
+
+	openReports, err := s.reviewRepo.CountOpenReports(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.ReportThreshold > 0 && openReports >= s.config.ReportThreshold {
+		reason := fmt.Sprintf("reported by %d raters", openReports)
+		// 并发举报时只有一次撤回生效
+		if _, err := s.reviewRepo.SetStatus(reviewID, models.ReviewStatusApproved, models.ReviewStatusPending, AutoModeratorName, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// isValidReportReason 判断举报原因是否受支持
+func isValidReportReason(reason string) bool {
+	for _, allowed := range models.ReviewReportReasons {
+		if reason == allowed {
+			return true
+		}
+	}
+	return false
+}
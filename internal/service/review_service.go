@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// ReviewService 评论服务接口
+type ReviewService interface {
+	ListReviews(movieTitle, sortBy, viewerID string, limit int, cursor string) (*models.ReviewPage, error)
+	GetReview(id int64, viewerID string, moderator bool) (*models.Review, error)
+	Vote(id int64, voterID string, helpful bool) (*models.Review, error)
+	RemoveVote(id int64, voterID string) error
+	AddReaction(id int64, raterID, reaction string) (*models.Review, error)
+	RemoveReaction(id int64, raterID, reaction string) error
+}
+
+// reviewService 评论服务实现
+type reviewService struct {
+	reviewRepo repository.ReviewRepository
+	movieRepo  repository.MovieRepository
+}
+
+// NewReviewService 创建评论服务实例
+func NewReviewService(reviewRepo repository.ReviewRepository, movieRepo repository.MovieRepository) ReviewService {
+	return &reviewService{
+		reviewRepo: reviewRepo,
+		movieRepo:  movieRepo,
+	}
+}
+
+// ListReviews 分页获取电影的评论，默认按时间倒序，sortBy=helpful时按有用程度排序
+func (s *reviewService) ListReviews(movieTitle, sortBy, viewerID string, limit int, cursor string) (*models.ReviewPage, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+
+	if sortBy == "" {
+		sortBy = models.ReviewSortRecent
+	}
+	return s.reviewRepo.ListByMovie(movie.Title, sortBy, viewerID, limit, cursor)
+}
+
+// GetReview 获取评论；未公开的评论只对作者和版主可见
+func (s *reviewService) GetReview(id int64, viewerID string, moderator bool) (*models.Review, error) {
+	review, err := s.reviewRepo.GetByID(id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, fmt.Errorf("review not found")
+	}
+	if review.Status != models.ReviewStatusApproved && !moderator && (viewerID == "" || review.RaterID != viewerID) {
+		return nil, fmt.Errorf("review not found")
+	}
+	return review, nil
+}
+
+// getPublicReview 获取已公开的评论，投票和回应只针对已公开的评论
+func (s *reviewService) getPublicReview(id int64, viewerID string) (*models.Review, error) {
+	review, err := s.reviewRepo.GetByID(id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil || review.Status != models.ReviewStatusApproved {
+		return nil, fmt.Errorf("review not found")
+	}
+	return review, nil
+}
+
+// Vote 对评论投有用或无用票，再次投票覆盖之前的投票；不能对自己的评论投票
+func (s *reviewService) Vote(id int64, voterID string, helpful bool) (*models.Review, error) {
+	review, err := s.getPublicReview(id, voterID)
+	if err != nil {
+		return nil, err
+	}
+	if review.RaterID == voterID {
+		return nil, fmt.Errorf("cannot vote on your own review")
+	}
+
+	voted, err := s.reviewRepo.Vote(id, voterID, helpful)
+	if err != nil {
+		return nil, err
+	}
+	if !voted {
+		// 评论在读取后被删除或撤下
+		return nil, fmt.Errorf("review not found")
+	}
+
+	return s.getPublicReview(id, voterID)
+}
+
+// RemoveVote 撤回对评论的投票
+func (s *reviewService) RemoveVote(id int64, voterID string) error {
+	removed, err := s.reviewRepo.DeleteVote(id, voterID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("vote not found")
+	}
+	return nil
+}
+
+// AddReaction 对评论添加表情回应
+func (s *reviewService) AddReaction(id int64, raterID, reaction string) (*models.Review, error) {
+	if !isValidReaction(reaction) {
+		return nil, fmt.Errorf("invalid reaction '%s', must be one of: %s", reaction, strings.Join(models.ReviewReactions, ", "))
+	}
+
+	if _, err := s.getPublicReview(id, raterID); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.AddReaction(id, raterID, reaction); err != nil {
+		return nil, err
+	}
+
+	return s.getPublicReview(id, raterID)
+}
+
+// RemoveReaction 移除对评论的表情回应
+func (s *reviewService) RemoveReaction(id int64, raterID, reaction string) error {
+	removed, err := s.reviewRepo.RemoveReaction(id, raterID, reaction)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("reaction not found")
+	}
+	return nil
+}
+
+// isValidReaction 判断表情回应是否受支持
+func isValidReaction(reaction string) bool {
+	for _, allowed := range models.ReviewReactions {
+		if reaction == allowed {
+			return true
+		}
+	}
+	return false
+}
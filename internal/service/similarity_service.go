@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// SimilarityConfig 协同过滤相似度计算参数
+type SimilarityConfig struct {
+	// MinCommonRaters 两部电影至少有多少位共同评分者才计算相似度
+	MinCommonRaters int
+	// NeighborsPerMovie 每部电影保存的相似电影数
+	NeighborsPerMovie int
+}
+
+// DefaultSimilarityConfig 默认相似度计算参数
+func DefaultSimilarityConfig() SimilarityConfig {
+	return SimilarityConfig{
+		MinCommonRaters:   3,
+		NeighborsPerMovie: 20,
+	}
+}
+
+// SimilarityService 电影相似度服务接口
+type SimilarityService interface {
+	ComputeSimilarities() (int, error)
+	GetSimilarMovies(movieTitle string, limit int) ([]models.SimilarMovie, error)
+}
+
+// similarityService 电影相似度服务实现
+type similarityService struct {
+	similarityRepo repository.SimilarityRepository
+	movieRepo      repository.MovieRepository
+	config         SimilarityConfig
+}
+
+// NewSimilarityService 创建电影相似度服务实例
+func NewSimilarityService(similarityRepo repository.SimilarityRepository, movieRepo repository.MovieRepository, config SimilarityConfig) SimilarityService {
+	return &similarityService{
+		similarityRepo: similarityRepo,
+		movieRepo:      movieRepo,
+		config:         config,
+	}
+}
+
+// moviePair 一对电影，a按字典序小于b
+type moviePair struct {
+	a, b string
+}
+
+// pairAccumulator 一对电影在共同评分者上的累计量
+type pairAccumulator struct {
+	dot, normA, normB float64
+	common            int
+}
+
+// ComputeSimilarities 按调整余弦相似度重算全部电影之间的相似度并保存，返回保存的相似度条数。
+// 每个评分先减去该评分者的平均分，消除评分者整体偏高或偏低的影响
+func (s *similarityService) ComputeSimilarities() (int, error) {
+	ratings, err := s.similarityRepo.ListRatings()
+	if err != nil {
+		return 0, err
+	}
+
+	// 按评分者分组（评分已按评分者排序）
+	accumulators := make(map[moviePair]*pairAccumulator)
+	for start := 0; start < len(ratings); {
+		end := start
+		sum := 0.0
+		for end < len(ratings) && ratings[end].RaterID == ratings[start].RaterID {
+			sum += ratings[end].Rating
+			end++
+		}
+		raterRatings := ratings[start:end]
+		start = end
+
+		// 只评过一部电影的评分者对相似度没有贡献
+		if len(raterRatings) < 2 {
+			continue
+		}
+		mean := sum / float64(len(raterRatings))
+
+		for i := 0; i < len(raterRatings); i++ {
+			for j := i + 1; j < len(raterRatings); j++ {
+				a, b := raterRatings[i], raterRatings[j]
+				if a.MovieTitle > b.MovieTitle {
+					a, b = b, a
+				}
+				pair := moviePair{a: a.MovieTitle, b: b.MovieTitle}
+				acc, ok := accumulators[pair]
+				if !ok {
+					acc = &pairAccumulator{}
+					accumulators[pair] = acc
+				}
+				da, db := a.Rating-mean, b.Rating-mean
+				acc.dot += da * db
+				acc.normA += da * da
+				acc.normB += db * db
+				acc.common++
+			}
+		}
+	}
+
+	// 只保留正相关且共同评分者足够的电影对，双向记录
+	neighbors := make(map[string][]models.MovieSimilarity)
+	for pair, acc := range accumulators {
+		if acc.common < s.config.MinCommonRaters || acc.normA == 0 || acc.normB == 0 {
+			continue
+		}
+		score := acc.dot / math.Sqrt(acc.normA*acc.normB)
+		if score <= 0 {
+			continue
+		}
+		neighbors[pair.a] = append(neighbors[pair.a], models.MovieSimilarity{
+			MovieTitle: pair.a, SimilarTitle: pair.b, Score: score, CommonRaters: acc.common,
+		})
+		neighbors[pair.b] = append(neighbors[pair.b], models.MovieSimilarity{
+			MovieTitle: pair.b, SimilarTitle: pair.a, Score: score, CommonRaters: acc.common,
+		})
+	}
+
+	// 每部电影只保存最相似的若干部
+	similarities := []models.MovieSimilarity{}
+	for _, list := range neighbors {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			if list[i].CommonRaters != list[j].CommonRaters {
+				return list[i].CommonRaters > list[j].CommonRaters
+			}
+			return list[i].SimilarTitle < list[j].SimilarTitle
+		})
+		if len(list) > s.config.NeighborsPerMovie {
+			list = list[:s.config.NeighborsPerMovie]
+		}
+		similarities = append(similarities, list...)
+	}
+
+	if err := s.similarityRepo.ReplaceAll(similarities); err != nil {
+		return 0, err
+	}
+	return len(similarities), nil
+}
+
+// GetSimilarMovies 获取与电影相似的电影；评分数据不足以计算相似度的电影按类型和发行商回退
+func (s *similarityService) GetSimilarMovies(movieTitle string, limit int) ([]models.SimilarMovie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+
+	similar, err := s.similarityRepo.ListSimilar(movie.Title, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(similar) > 0 {
+		return similar, nil
+	}
+
+	return s.similarityRepo.ListByAttributes(movie, limit)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"movie-rating-api/internal/models"
+	"movie-rating-api/internal/repository"
+)
+
+// TagService 电影标签服务接口
+type TagService interface {
+	AddTag(movieTitle, raterID, tag string) (*models.MovieTag, bool, error)
+	RemoveTag(movieTitle, raterID, tag string) error
+	ListMovieTags(movieTitle, viewerID string, limit int) ([]models.MovieTag, error)
+	ListPopularTags(prefix string, limit int, cursor string) (*models.TagPage, error)
+	ListTrendingTags(window time.Duration, limit int) ([]models.Tag, error)
+}
+
+// tagService 电影标签服务实现
+type tagService struct {
+	tagRepo   repository.TagRepository
+	movieRepo repository.MovieRepository
+}
+
+// NewTagService 创建电影标签服务实例
+func NewTagService(tagRepo repository.TagRepository, movieRepo repository.MovieRepository) TagService {
+	return &tagService{
+		tagRepo:   tagRepo,
+		movieRepo: movieRepo,
+	}
+}
+
+// AddTag 评分者为电影添加标签，返回该标签在电影上的汇总以及是否新添加
+func (s *tagService) AddTag(movieTitle, raterID, tag string) (*models.MovieTag, bool, error) {
+	name := models.NormalizeTag(tag)
+	if name == "" {
+		return nil, false, fmt.Errorf("tag is required")
+	}
+	if utf8.RuneCountInString(name) > models.MaxTagLength {
+		return nil, false, fmt.Errorf("tag must be at most %d characters", models.MaxTagLength)
+	}
+	key := models.TagKey(name)
+	if key == "" {
+		return nil, false, fmt.Errorf("invalid tag: must contain a letter or digit")
+	}
+
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	added, err := s.tagRepo.Add(movie.ID, raterID, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	movieTag, err := s.tagRepo.GetMovieTag(movie.ID, key, raterID)
+	if err != nil {
+		return nil, false, err
+	}
+	if movieTag == nil {
+		// 标签在添加后被撤回
+		return nil, false, fmt.Errorf("tag not found")
+	}
+
+	return movieTag, added, nil
+}
+
+// RemoveTag 撤回评分者为电影添加的标签
+func (s *tagService) RemoveTag(movieTitle, raterID, tag string) error {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.tagRepo.Remove(movie.ID, raterID, models.TagKey(tag))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("tag not found")
+	}
+	return nil
+}
+
+// ListMovieTags 获取电影上添加人数最多的标签
+func (s *tagService) ListMovieTags(movieTitle, viewerID string, limit int) ([]models.MovieTag, error) {
+	movie, err := s.getMovie(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.ListByMovie(movie.ID, viewerID, limit)
+}
+
+// ListPopularTags 分页获取最常用的标签，prefix不为空时只列出以其开头的标签
+func (s *tagService) ListPopularTags(prefix string, limit int, cursor string) (*models.TagPage, error) {
+	return s.tagRepo.ListPopular(models.TagKey(prefix), limit, cursor)
+}
+
+// ListTrendingTags 获取最近window时间内添加次数最多的标签
+func (s *tagService) ListTrendingTags(window time.Duration, limit int) ([]models.Tag, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid window: must be positive")
+	}
+
+	return s.tagRepo.ListTrending(time.Now().Add(-window), limit)
+}
+
+// getMovie 根据标题或别名获取电影
+func (s *tagService) getMovie(movieTitle string) (*models.Movie, error) {
+	movie, err := s.movieRepo.GetByTitle(movieTitle)
+	if err != nil {
+		return nil, err
+	}
+	if movie == nil {
+		return nil, fmt.Errorf("movie not found")
+	}
+	return movie, nil
+}